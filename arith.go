@@ -0,0 +1,102 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+var (
+	// ErrOverflow is returned when a result exceeds the largest size.
+	ErrOverflow = errors.New("bytefmt: size overflow")
+	// ErrUnderflow is returned when a result is less than zero.
+	ErrUnderflow = errors.New("bytefmt: size underflow")
+	// ErrDivisionByZero is returned when dividing by zero.
+	ErrDivisionByZero = errors.New("bytefmt: division by zero")
+)
+
+// Add returns b+x, or ErrOverflow if the sum does not fit into uint64.
+func (b Bytes) Add(x Bytes) (Bytes, error) {
+	v, carry := bits.Add64(b.Value, x.Value, 0)
+	if carry != 0 {
+		return b.with(math.MaxUint64), ErrOverflow
+	}
+	return b.with(v), nil
+}
+
+// AddSat returns b+x, saturated at the largest size.
+func (b Bytes) AddSat(x Bytes) Bytes {
+	s, _ := b.Add(x)
+	return s
+}
+
+// Sub returns b-x, or ErrUnderflow if x is greater than b.
+func (b Bytes) Sub(x Bytes) (Bytes, error) {
+	v, borrow := bits.Sub64(b.Value, x.Value, 0)
+	if borrow != 0 {
+		return b.with(0), ErrUnderflow
+	}
+	return b.with(v), nil
+}
+
+// SubSat returns b-x, or zero if x is greater than b.
+func (b Bytes) SubSat(x Bytes) Bytes {
+	d, _ := b.Sub(x)
+	return d
+}
+
+// Mul returns b*n, or ErrOverflow if the product does not fit into uint64.
+func (b Bytes) Mul(n uint64) (Bytes, error) {
+	hi, v := bits.Mul64(b.Value, n)
+	if hi != 0 {
+		return b.with(math.MaxUint64), ErrOverflow
+	}
+	return b.with(v), nil
+}
+
+// MulSat returns b*n, saturated at the largest size.
+func (b Bytes) MulSat(n uint64) Bytes {
+	p, _ := b.Mul(n)
+	return p
+}
+
+// Div returns b/n rounded down, or ErrDivisionByZero if n is zero.
+func (b Bytes) Div(n uint64) (Bytes, error) {
+	if n == 0 {
+		return b.with(0), ErrDivisionByZero
+	}
+	return b.with(b.Value / n), nil
+}
+
+// Sum returns the sum of s, or ErrOverflow if it does not fit into uint64.
+// The result has the names of the first element.
+func Sum(s ...Bytes) (Bytes, error) {
+	var sum Bytes
+	if len(s) != 0 {
+		sum = s[0].with(0)
+	}
+	for _, b := range s {
+		var err error
+		sum, err = sum.Add(b)
+		if err != nil {
+			return sum, err
+		}
+	}
+	return sum, nil
+}
+
+// SumSat returns the sum of s, saturated at the largest size.
+func SumSat(s ...Bytes) Bytes {
+	sum, _ := Sum(s...)
+	return sum
+}
+
+// with returns the value v with the names of b.
+func (b Bytes) with(v uint64) Bytes {
+	b.Value = v
+	return b
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var arithTests = []struct {
+	name    string
+	line    string
+	op      func() (bytefmt.Bytes, error)
+	sat     func() bytefmt.Bytes
+	want    uint64
+	wantSat uint64
+	err     error
+}{
+	{
+		name:    "add",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(1 << 30).Add(bytefmt.New(512 << 20)) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(1 << 30).AddSat(bytefmt.New(512 << 20)) },
+		want:    1536 << 20,
+		wantSat: 1536 << 20,
+	}, {
+		name:    "add overflow",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(math.MaxUint64).Add(bytefmt.New(1)) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(math.MaxUint64).AddSat(bytefmt.New(1)) },
+		want:    math.MaxUint64,
+		wantSat: math.MaxUint64,
+		err:     bytefmt.ErrOverflow,
+	}, {
+		name:    "sub",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(3 << 20).Sub(bytefmt.New(1 << 20)) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(3 << 20).SubSat(bytefmt.New(1 << 20)) },
+		want:    2 << 20,
+		wantSat: 2 << 20,
+	}, {
+		name:    "sub underflow",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(1).Sub(bytefmt.New(2)) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(1).SubSat(bytefmt.New(2)) },
+		want:    0,
+		wantSat: 0,
+		err:     bytefmt.ErrUnderflow,
+	}, {
+		name:    "mul",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(4 << 30).Mul(3) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(4 << 30).MulSat(3) },
+		want:    12 << 30,
+		wantSat: 12 << 30,
+	}, {
+		name:    "mul overflow",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.New(8 << 60).Mul(2) },
+		sat:     func() bytefmt.Bytes { return bytefmt.New(8 << 60).MulSat(2) },
+		want:    math.MaxUint64,
+		wantSat: math.MaxUint64,
+		err:     bytefmt.ErrOverflow,
+	}, {
+		name:    "sum",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.Sum(bytefmt.New(1), bytefmt.New(2), bytefmt.New(3)) },
+		sat:     func() bytefmt.Bytes { return bytefmt.SumSat(bytefmt.New(1), bytefmt.New(2), bytefmt.New(3)) },
+		want:    6,
+		wantSat: 6,
+	}, {
+		name:    "empty sum",
+		line:    testline(),
+		op:      func() (bytefmt.Bytes, error) { return bytefmt.Sum() },
+		sat:     func() bytefmt.Bytes { return bytefmt.SumSat() },
+		want:    0,
+		wantSat: 0,
+	}, {
+		name: "sum overflow",
+		line: testline(),
+		op: func() (bytefmt.Bytes, error) {
+			return bytefmt.Sum(bytefmt.New(8<<60), bytefmt.New(8<<60), bytefmt.New(1))
+		},
+		sat: func() bytefmt.Bytes {
+			return bytefmt.SumSat(bytefmt.New(8<<60), bytefmt.New(8<<60), bytefmt.New(1))
+		},
+		want:    math.MaxUint64,
+		wantSat: math.MaxUint64,
+		err:     bytefmt.ErrOverflow,
+	},
+}
+
+func TestArith(t *testing.T) {
+	for _, tt := range arithTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.op()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if got.Value != tt.want {
+				t.Errorf("\nwant bytes: %d\n got bytes: %d\ntest: %s", tt.want, got.Value, tt.line)
+			}
+			if sat := tt.sat(); sat.Value != tt.wantSat {
+				t.Errorf("\nwant saturated bytes: %d\n got saturated bytes: %d\ntest: %s", tt.wantSat, sat.Value, tt.line)
+			}
+		})
+	}
+}
+
+func TestDiv(t *testing.T) {
+	got, err := bytefmt.New(10).Div(3)
+	if err != nil || got.Value != 3 {
+		t.Errorf("\nwant bytes: 3 <nil>\n got bytes: %d %v", got.Value, err)
+	}
+	_, err = bytefmt.New(10).Div(0)
+	if !errors.Is(err, bytefmt.ErrDivisionByZero) {
+		t.Errorf("\nwant error: %v\n got error: %v", bytefmt.ErrDivisionByZero, err)
+	}
+}
+
+func TestArithKeepsNames(t *testing.T) {
+	b, _ := bytefmt.New(1024, "B", "Kilobyte").Add(bytefmt.New(1024))
+	got := b.Names()
+	if got[1] != "Kilobyte" {
+		t.Errorf("\nwant name: %#v\n got name: %#v", "Kilobyte", got[1])
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -24,6 +25,7 @@ const (
 type Bytes struct {
 	Value uint64
 	names []string
+	si    bool
 }
 
 func New(v uint64, n ...string) Bytes {
@@ -32,23 +34,41 @@ func New(v uint64, n ...string) Bytes {
 	return b
 }
 
-var names = []string{"B", "K", "M", "G", "T", "P", "E"}
+// NewSI returns bytes formatted in powers of 1000 rather than 1024,
+// with the units of measure named kB, MB, GB and so on by default.
+func NewSI(v uint64, n ...string) Bytes {
+	b := Bytes{Value: v, si: true}
+	b.Names(n...)
+	return b
+}
+
+var (
+	names   = []string{"B", "K", "M", "G", "T", "P", "E"}
+	siNames = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
 
 func (b *Bytes) Names(n ...string) []string {
+	def := names
+	if b.si {
+		def = siNames
+	}
 	if len(n) == 0 {
 		if len(b.names) == 0 {
-			b.names = names
+			b.names = def
 		}
 		return b.names
 	}
 	b.names = n
-	if len(b.names) < len(names) {
-		b.names = append(b.names, names[len(b.names):]...)
+	if len(b.names) < len(def) {
+		b.names = append(b.names, def[len(b.names):]...)
 	}
 	return b.names
 }
 
 func (b Bytes) String() string {
+	if b.si {
+		return fmt.Sprintf("%v%s", b.float64(), siNames[b.siExp()])
+	}
 	var (
 		f float64
 		i int
@@ -87,11 +107,17 @@ func (b Bytes) String() string {
 */
 
 func (b Bytes) Format(f fmt.State, c rune) {
+	b.format(f, c, false)
+}
+
+// format writes b formatted by the verb c, negated if neg is true.
+func (b Bytes) format(f fmt.State, c rune, neg bool) {
 	var (
 		v  interface{}      // value
 		vf = "%"            // value format
 		uf = "%"            // unit format
 		pf = ""             // padding format
+		zp = false          // pad with zeros after the sign
 		u  = b.name()       // unit of measure name
 		uw = len([]rune(u)) // unit name width
 	)
@@ -116,7 +142,7 @@ func (b Bytes) Format(f fmt.State, c rune) {
 		if f.Flag('-') {
 			pf += "-"
 		} else if f.Flag('0') {
-			pf += "0"
+			zp = true
 		}
 		pf += strconv.Itoa(w) + "s"
 	}
@@ -125,31 +151,55 @@ func (b Bytes) Format(f fmt.State, c rune) {
 	}
 	vf += string(c)
 	uf += "s"
+	n := b.float64()
+	if neg {
+		n = -n
+	}
 	var s string
 	if c == 's' || c == 'q' {
-		v = fmt.Sprintf("%v", b.float64())
+		v = fmt.Sprintf("%v", n)
 		s = fmt.Sprint(v) + fmt.Sprintf(uf, u)
-		if pf != "" {
+		if zp {
+			s = zeroPad(s, w)
+		} else if pf != "" {
 			s = fmt.Sprintf(pf, s)
 		}
 		s = fmt.Sprintf(vf, s)
 	} else {
 		if c == 'd' {
-			v = int64(math.Round(b.float64()))
+			v = int64(math.Round(n))
 		} else {
-			v = b.float64()
+			v = n
 		}
 		s = fmt.Sprintf(vf+uf, v, u)
-		if pf != "" {
+		if zp {
+			s = zeroPad(s, w)
+		} else if pf != "" {
 			s = fmt.Sprintf(pf, s)
 		}
 	}
 	f.Write([]byte(s))
 }
 
+// zeroPad pads s with leading zeros to the width w,
+// the zeros are put after the sign if any, e.g. -001.5K.
+func zeroPad(s string, w int) string {
+	i := 0
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		i = 1
+	}
+	if n := w - width(s); n > 0 {
+		return s[:i] + strings.Repeat("0", n) + s[i:]
+	}
+	return s
+}
+
 // float64 returns returns a number in units of measure
 // when converted to which the smallest integer is obtained
 func (b Bytes) float64() float64 {
+	if b.si {
+		return float64(b.Value) / float64(pow10(3*b.siExp()))
+	}
 	if b.Value >= Exabyte {
 		return b.exabytes()
 	} else if b.Value >= Petabyte {
@@ -167,6 +217,12 @@ func (b Bytes) float64() float64 {
 	}
 }
 
+// short returns a number with at most one decimal and the name
+// of the unit of measure, e.g. 1.5G or 512M
+func (b Bytes) short() string {
+	return strings.TrimSuffix(strconv.FormatFloat(b.float64(), 'f', 1, 64), ".0") + b.name()
+}
+
 func (b Bytes) kilobytes() float64 { return float64(b.Value) / float64(Kilobyte) }
 func (b Bytes) megabytes() float64 { return float64(b.Value) / float64(Megabyte) }
 func (b Bytes) gigabytes() float64 { return float64(b.Value) / float64(Gigabyte) }
@@ -177,19 +233,39 @@ func (b Bytes) exabytes() float64  { return float64(b.Value) / float64(Exabyte)
 // name returns the name of the unit of measure
 // when converted to which the smallest integer is obtained
 func (b Bytes) name() string {
+	n := b.names
+	if len(n) == 0 {
+		n = names
+		if b.si {
+			n = siNames
+		}
+	}
+	if b.si {
+		return n[b.siExp()]
+	}
 	if b.Value >= Exabyte {
-		return b.names[6]
+		return n[6]
 	} else if b.Value >= Petabyte {
-		return b.names[5]
+		return n[5]
 	} else if b.Value >= Terabyte {
-		return b.names[4]
+		return n[4]
 	} else if b.Value >= Gigabyte {
-		return b.names[3]
+		return n[3]
 	} else if b.Value >= Megabyte {
-		return b.names[2]
+		return n[2]
 	} else if b.Value >= Kilobyte {
-		return b.names[1]
+		return n[1]
 	} else {
-		return b.names[0]
+		return n[0]
+	}
+}
+
+// siExp returns the power of 1000 of the unit of measure
+// when converted to which the smallest integer is obtained
+func (b Bytes) siExp() int {
+	i := 0
+	for v := b.Value; v >= 1000 && i < 6; v /= 1000 {
+		i++
 	}
+	return i
 }
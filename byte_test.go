@@ -2232,6 +2232,74 @@ func BenchmarkBytesFormat(b *testing.B) {
 	}
 }
 
+var bytesSITests = []struct {
+	name   string
+	line   string
+	bytes  uint64
+	format string
+	names  []string
+	want   string
+}{
+	{
+		name:   "bytes",
+		line:   testline(),
+		bytes:  999,
+		format: "%v",
+		want:   "999B",
+	}, {
+		name:   "exactly one kilobyte",
+		line:   testline(),
+		bytes:  1000,
+		format: "%v",
+		want:   "1kB",
+	}, {
+		name:   "binary kilobyte",
+		line:   testline(),
+		bytes:  1024,
+		format: "%v",
+		want:   "1.024kB",
+	}, {
+		name:   "precision",
+		line:   testline(),
+		bytes:  1610612736,
+		format: "% .1f",
+		want:   "1.6 GB",
+	}, {
+		name:   "names",
+		line:   testline(),
+		bytes:  1500000,
+		format: "%.2f",
+		names:  []string{"B", "k", "M"},
+		want:   "1.50M",
+	}, {
+		name:   "exabytes",
+		line:   testline(),
+		bytes:  18446744073709551615,
+		format: "%.1f",
+		want:   "18.4EB",
+	},
+}
+
+func TestBytesSI(t *testing.T) {
+	for _, tt := range bytesSITests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.format+" "+strconv.FormatUint(tt.bytes, 10), func(t *testing.T) {
+			t.Parallel()
+
+			got := fmt.Sprintf(tt.format, bytefmt.NewSI(tt.bytes, tt.names...))
+			if got != tt.want {
+				t.Errorf("\nwant format: %#v\n got format: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestBytesSIString(t *testing.T) {
+	if got := bytefmt.NewSI(1500).String(); got != "1.5kB" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "1.5kB", got)
+	}
+}
+
 func testline() string {
 	_, file, line, ok := runtime.Caller(1)
 	if ok {
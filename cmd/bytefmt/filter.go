@@ -0,0 +1,95 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// runFilter runs the filter command.
+func runFilter(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bytefmt filter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bytefmt filter [-field list] [-d delim] [-f format] [-system legacy|si|iec] [-padding n] [-header n]")
+		fs.PrintDefaults()
+	}
+	field := fs.String("field", "", "fields to humanize, e.g. 5 or 2,4-6; all if empty")
+	delim := fs.String("d", "", "field delimiter; runs of blanks if empty")
+	format := fs.String("f", "", "format of the sizes, e.g. '% .1f'; 1.5G if empty")
+	system := fs.String("system", "legacy", "units of measure: legacy, si or iec")
+	padding := fs.Int("padding", 0, "pad the sizes to the width, on the right if negative")
+	header := fs.Int("header", 0, "number of header lines to copy unchanged")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	fields, err := parseFields(*field)
+	if err != nil {
+		fmt.Fprintf(stderr, "bytefmt: invalid field list %q: %v\n", *field, err)
+		return 2
+	}
+	si, names, ok := units(*system)
+	if !ok {
+		fmt.Fprintf(stderr, "bytefmt: unknown system %q\n", *system)
+		fs.Usage()
+		return 2
+	}
+	err = bytefmt.Filter(stdout, stdin, &bytefmt.FilterOptions{
+		Fields:    fields,
+		Delimiter: *delim,
+		Format:    *format,
+		SI:        si,
+		Names:     names,
+		Padding:   *padding,
+		Header:    *header,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "bytefmt:", err)
+		return 1
+	}
+	return 0
+}
+
+var errFieldRange = errors.New("fields are numbered from 1")
+
+// parseFields parses a list of fields such as 2,4-6.
+func parseFields(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var fields []int
+	for _, r := range strings.Split(s, ",") {
+		first, last := r, r
+		if i := strings.IndexByte(r, '-'); i != -1 {
+			first, last = r[:i], r[i+1:]
+		}
+		f, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, err
+		}
+		l, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, err
+		}
+		if f < 1 || l < f {
+			return nil, errFieldRange
+		}
+		for ; f <= l; f++ {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
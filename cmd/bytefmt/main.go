@@ -0,0 +1,154 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Bytefmt converts numbers of bytes to human sizes and back.
+//
+// Usage:
+//
+//	bytefmt [-f format] [-system legacy|si|iec] [value ...]
+//	bytefmt filter [-field list] [-d delim] [-f format] [-system ...] [-padding n] [-header n]
+//	bytefmt sort [-r]
+//
+// A plain integer is formatted as a size using the verbs and flags
+// of bytefmt.Bytes, e.g. bytefmt -f '% .1f' 1610612736 prints 1.5 G;
+// any other value is parsed as a size and printed as a number of bytes,
+// e.g. bytefmt 1.5G prints 1610612736.
+// Without arguments the values are read from the standard input,
+// one per line.
+//
+// The -system flag chooses the units of measure of the formatted sizes:
+// legacy for powers of 1024 named K, M, G (the default),
+// si for powers of 1000 named kB, MB, GB
+// and iec for powers of 1024 named KiB, MiB, GiB.
+//
+// The filter command copies the standard input to the standard output
+// replacing the numbers in the fields listed by -field, e.g. 5 or 2,4-6,
+// with sizes, as numfmt --field does; see bytefmt.Filter.
+//
+// The sort command sorts the lines of the standard input by the sizes
+// at their start, as sort -h does, e.g. du -h | bytefmt sort -r;
+// see bytefmt.Compare.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pfmt/bytefmt"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+var iecNames = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// run runs the command with the arguments and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "filter":
+			return runFilter(args[1:], stdin, stdout, stderr)
+		case "sort":
+			return runSort(args[1:], stdin, stdout, stderr)
+		}
+	}
+	fs := flag.NewFlagSet("bytefmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bytefmt [-f format] [-system legacy|si|iec] [value ...]")
+		fs.PrintDefaults()
+	}
+	format := fs.String("f", "%v", "format of the sizes, e.g. '% .1f'")
+	system := fs.String("system", "legacy", "units of measure: legacy, si or iec")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	si, names, ok := units(*system)
+	if !ok {
+		fmt.Fprintf(stderr, "bytefmt: unknown system %q\n", *system)
+		fs.Usage()
+		return 2
+	}
+	newBytes := func(v uint64) bytefmt.Bytes {
+		if si {
+			return bytefmt.NewSI(v, names...)
+		}
+		return bytefmt.New(v, names...)
+	}
+
+	w := bufio.NewWriter(stdout)
+	status := 0
+	convert := func(s string) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return
+		}
+		if isInteger(s) {
+			v, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				fmt.Fprintf(stderr, "bytefmt: %q: %v\n", s, bytefmt.ErrRange)
+				status = 1
+				return
+			}
+			fmt.Fprintf(w, *format+"\n", newBytes(v))
+			return
+		}
+		b, err := bytefmt.Parse(s)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			status = 1
+			return
+		}
+		fmt.Fprintln(w, b.Value)
+	}
+
+	if fs.NArg() > 0 {
+		for _, s := range fs.Args() {
+			convert(s)
+		}
+	} else {
+		sc := bufio.NewScanner(stdin)
+		for sc.Scan() {
+			convert(sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			fmt.Fprintln(stderr, "bytefmt:", err)
+			status = 1
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(stderr, "bytefmt:", err)
+		return 1
+	}
+	return status
+}
+
+// units returns the units of measure of the system.
+func units(system string) (si bool, names []string, ok bool) {
+	switch system {
+	case "legacy":
+		return false, nil, true
+	case "si":
+		return true, nil, true
+	case "iec":
+		return false, iecNames, true
+	}
+	return false, nil, false
+}
+
+// isInteger reports whether s consists of decimal digits only.
+func isInteger(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var runTests = []struct {
+	name   string
+	line   string
+	args   []string
+	stdin  string
+	stdout string
+	stderr string
+	status int
+}{
+	{
+		name:   "number",
+		line:   testline(),
+		args:   []string{"1610612736"},
+		stdout: "1.5G\n",
+	}, {
+		name:   "format",
+		line:   testline(),
+		args:   []string{"-f", "% .1f", "1610612736"},
+		stdout: "1.5 G\n",
+	}, {
+		name:   "si",
+		line:   testline(),
+		args:   []string{"-system", "si", "-f", "% .1f", "1610612736"},
+		stdout: "1.6 GB\n",
+	}, {
+		name:   "iec",
+		line:   testline(),
+		args:   []string{"-system=iec", "-f", "%.2f", "1610612736", "1024"},
+		stdout: "1.50GiB\n1.00KiB\n",
+	}, {
+		name:   "size",
+		line:   testline(),
+		args:   []string{"1.5G", "10MB", "1KiB"},
+		stdout: "1610612736\n10000000\n1024\n",
+	}, {
+		name:   "stdin",
+		line:   testline(),
+		stdin:  "512\n\n  4096 \n2M\n",
+		stdout: "512B\n4K\n2097152\n",
+	}, {
+		name:   "invalid",
+		line:   testline(),
+		args:   []string{"12Q", "1024"},
+		stdout: "1K\n",
+		stderr: "bytefmt: parsing \"12Q\": invalid syntax\n",
+		status: 1,
+	}, {
+		name:   "too large",
+		line:   testline(),
+		args:   []string{"18446744073709551616"},
+		stderr: "bytefmt: \"18446744073709551616\": value out of range\n",
+		status: 1,
+	}, {
+		name:   "unknown system",
+		line:   testline(),
+		args:   []string{"-system", "metric", "1"},
+		stderr: "bytefmt: unknown system \"metric\"\nusage: bytefmt",
+		status: 2,
+	}, {
+		name:   "unknown flag",
+		line:   testline(),
+		args:   []string{"-x"},
+		stderr: "flag provided but not defined: -x\nusage: bytefmt",
+		status: 2,
+	}, {
+		name:   "filter",
+		line:   testline(),
+		args:   []string{"filter", "-field", "2"},
+		stdin:  "a     1536 4096\nb 10485760 8192\n",
+		stdout: "a     1.5K 4096\nb      10M 8192\n",
+	}, {
+		name:   "filter fields",
+		line:   testline(),
+		args:   []string{"filter", "-field", "2-3", "-d", ",", "-f", "%.1f", "-system", "iec", "-header", "1"},
+		stdin:  "name,size,used\na,1536,1024\n",
+		stdout: "name,size,used\na,1.5KiB,1.0KiB\n",
+	}, {
+		name:   "filter invalid field",
+		line:   testline(),
+		args:   []string{"filter", "-field", "3-2"},
+		stderr: "bytefmt: invalid field list \"3-2\": fields are numbered from 1\n",
+		status: 2,
+	}, {
+		name:   "filter arguments",
+		line:   testline(),
+		args:   []string{"filter", "file"},
+		stderr: "usage: bytefmt filter",
+		status: 2,
+	}, {
+		name:   "sort",
+		line:   testline(),
+		args:   []string{"sort"},
+		stdin:  "1.2M\tb\n15G\tc\n900K\ta\n",
+		stdout: "900K\ta\n1.2M\tb\n15G\tc\n",
+	}, {
+		name:   "sort reverse",
+		line:   testline(),
+		args:   []string{"sort", "-r"},
+		stdin:  "4.0K\t./a\n1.5M\t.\n12K\t./b",
+		stdout: "1.5M\t.\n12K\t./b\n4.0K\t./a\n",
+	},
+}
+
+func TestRun(t *testing.T) {
+	for _, tt := range runTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr strings.Builder
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.status {
+				t.Errorf("\nwant status: %d\n got status: %d\ntest: %s", tt.status, status, tt.line)
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("\nwant stdout: %#v\n got stdout: %#v\ntest: %s", tt.stdout, stdout.String(), tt.line)
+			}
+			if !strings.HasPrefix(stderr.String(), tt.stderr) || tt.stderr == "" && stderr.Len() != 0 {
+				t.Errorf("\nwant stderr: %#v\n got stderr: %#v\ntest: %s", tt.stderr, stderr.String(), tt.line)
+			}
+		})
+	}
+}
+
+func testline() string {
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "it was not possible to recover file and line number information about function invocations"
+}
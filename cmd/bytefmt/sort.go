@@ -0,0 +1,56 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// runSort runs the sort command.
+func runSort(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bytefmt sort", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bytefmt sort [-r]")
+		fs.PrintDefaults()
+	}
+	reverse := fs.Bool("r", false, "reverse the result of comparisons")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	var lines []string
+	sc := bufio.NewScanner(stdin)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintln(stderr, "bytefmt:", err)
+		return 1
+	}
+	var x sort.Interface = bytefmt.HumanSlice(lines)
+	if *reverse {
+		x = sort.Reverse(x)
+	}
+	sort.Sort(x)
+	w := bufio.NewWriter(stdout)
+	for _, l := range lines {
+		fmt.Fprintln(w, l)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(stderr, "bytefmt:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// rateWindow is the window of the current rate of a counter.
+	rateWindow = 5 * time.Second
+	// rateInterval is the minimum time between samples of the rate,
+	// reading the rate more often does not sample the count again.
+	rateInterval = 100 * time.Millisecond
+)
+
+// counter is safe to read from another goroutine while it is updated.
+type counter struct {
+	n     uint64 // must be first for 64-bit alignment of atomic operations
+	start time.Time
+	now   func() time.Time
+	rate  *counterRate
+}
+
+// counterRate samples the count of a counter when its rate is read.
+type counterRate struct {
+	mu      sync.Mutex
+	w       *Window
+	sampled uint64    // count at the last sample
+	last    time.Time // time of the last sample
+	started bool
+}
+
+func newCounter() counter {
+	return counter{start: time.Now(), now: time.Now, rate: &counterRate{w: NewWindow(rateWindow)}}
+}
+
+func (c *counter) add(n int) {
+	if n > 0 {
+		atomic.AddUint64(&c.n, uint64(n))
+	}
+}
+
+// Count returns the number of bytes transferred so far.
+func (c *counter) Count() uint64 {
+	return atomic.LoadUint64(&c.n)
+}
+
+// Bytes returns the number of bytes transferred so far.
+func (c *counter) Bytes() Bytes {
+	return New(c.Count())
+}
+
+// Rate returns the current number of bytes transferred per second,
+// averaged over the last 5 seconds, or since the previous call
+// if it is longer ago, so that a stall brings the rate down to zero.
+// The count is sampled at most every 100 milliseconds.
+func (c *counter) Rate() Rate {
+	r := c.rate
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.started {
+		r.w.Add(0, c.start)
+		r.started, r.last = true, c.start
+	}
+	if now := c.now(); now.Sub(r.last) >= rateInterval {
+		n := c.Count()
+		r.w.Add(n-r.sampled, now)
+		r.sampled, r.last = n, now
+	}
+	return r.w.Rate()
+}
+
+// AverageRate returns the average number of bytes transferred per second
+// since the counter was created.
+func (c *counter) AverageRate() Rate {
+	d := c.now().Sub(c.start)
+	if d <= 0 {
+		return 0
+	}
+	return Rate(float64(c.Count()) / d.Seconds())
+}
+
+// CountingReader counts bytes read from the underlying reader.
+type CountingReader struct {
+	counter
+	r io.Reader
+}
+
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{counter: newCounter(), r: r}
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.add(n)
+	return n, err
+}
+
+// WriteTo uses the WriteTo method of the underlying reader if available.
+func (c *CountingReader) WriteTo(w io.Writer) (int64, error) {
+	if wt, ok := c.r.(io.WriterTo); ok {
+		return wt.WriteTo(&countingWriter{w: w, c: &c.counter})
+	}
+	return io.Copy(w, struct{ io.Reader }{c})
+}
+
+// CountingWriter counts bytes written to the underlying writer.
+type CountingWriter struct {
+	counter
+	w io.Writer
+}
+
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{counter: newCounter(), w: w}
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.add(n)
+	return n, err
+}
+
+// ReadFrom uses the ReadFrom method of the underlying writer if available.
+func (c *CountingWriter) ReadFrom(r io.Reader) (int64, error) {
+	if rf, ok := c.w.(io.ReaderFrom); ok {
+		return rf.ReadFrom(&countingReader{r: r, c: &c.counter})
+	}
+	return io.Copy(struct{ io.Writer }{c}, r)
+}
+
+type countingWriter struct {
+	w io.Writer
+	c *counter
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.c.add(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	c *counter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.c.add(n)
+	return n, err
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var countingTests = []struct {
+	name string
+	line string
+	copy func(src string) (int64, uint64, uint64, error)
+}{
+	{
+		name: "read",
+		line: testline(),
+		copy: func(src string) (int64, uint64, uint64, error) {
+			r := bytefmt.NewCountingReader(struct{ io.Reader }{strings.NewReader(src)})
+			n, err := io.Copy(io.Discard, r)
+			return n, r.Count(), uint64(len(src)), err
+		},
+	}, {
+		name: "write to passthrough",
+		line: testline(),
+		copy: func(src string) (int64, uint64, uint64, error) {
+			r := bytefmt.NewCountingReader(strings.NewReader(src))
+			var buf bytes.Buffer
+			n, err := r.WriteTo(&buf)
+			return n, r.Count(), uint64(buf.Len()), err
+		},
+	}, {
+		name: "write",
+		line: testline(),
+		copy: func(src string) (int64, uint64, uint64, error) {
+			var buf bytes.Buffer
+			w := bytefmt.NewCountingWriter(struct{ io.Writer }{&buf})
+			n, err := io.Copy(w, strings.NewReader(src))
+			return n, w.Count(), uint64(buf.Len()), err
+		},
+	}, {
+		name: "read from passthrough",
+		line: testline(),
+		copy: func(src string) (int64, uint64, uint64, error) {
+			var buf bytes.Buffer
+			w := bytefmt.NewCountingWriter(&buf)
+			n, err := w.ReadFrom(struct{ io.Reader }{strings.NewReader(src)})
+			return n, w.Count(), uint64(buf.Len()), err
+		},
+	},
+}
+
+func TestCounting(t *testing.T) {
+	src := strings.Repeat("x", 100500)
+	for _, tt := range countingTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n, count, copied, err := tt.copy(src)
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if n != int64(len(src)) || count != uint64(len(src)) || copied != uint64(len(src)) {
+				t.Errorf("\nwant count: %d\n got count: %d %d %d\ntest: %s", len(src), n, count, copied, tt.line)
+			}
+		})
+	}
+}
+
+func TestCountingConcurrent(t *testing.T) {
+	w := bytefmt.NewCountingWriter(io.Discard)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_, _ = w.Write(make([]byte, 1024))
+				_ = w.Bytes().String()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := w.Bytes(); got.Value != 4000*1024 {
+		t.Errorf("\nwant bytes: %d\n got bytes: %d", 4000*1024, got.Value)
+	}
+}
+
+func TestCountingRate(t *testing.T) {
+	now := time.Unix(0, 0)
+	r := bytefmt.NewCountingReader(strings.NewReader(strings.Repeat("x", 3<<20)))
+	r.SetClock(func() time.Time { return now })
+
+	if got := r.Rate(); got != 0 {
+		t.Errorf("\nwant rate: 0\n got rate: %v", got)
+	}
+	_, _ = io.Copy(io.Discard, r)
+	now = now.Add(2 * time.Second)
+	if got := r.Rate(); got != 3<<19 {
+		t.Errorf("\nwant rate: %d\n got rate: %v", 3<<19, got)
+	}
+}
+
+func TestCountingRatePolling(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := bytefmt.NewCountingWriter(io.Discard)
+	w.SetClock(func() time.Time { return now })
+
+	for i := 0; i < 100000; i++ {
+		if i%1000 == 0 {
+			now = now.Add(time.Millisecond)
+		}
+		_, _ = w.Write(make([]byte, 10))
+		_ = w.Rate()
+	}
+	now = now.Add(100 * time.Millisecond)
+	if got := w.Rate(); got != 5e6 {
+		t.Errorf("\nwant rate: %v\n got rate: %v", 5e6, got)
+	}
+	if n := w.RateSamples(); n > 2*50+2 {
+		t.Errorf("want at most %d samples, got %d", 2*50+2, n)
+	}
+}
+
+func TestCountingRateStall(t *testing.T) {
+	now := time.Unix(0, 0)
+	w := bytefmt.NewCountingWriter(io.Discard)
+	w.SetClock(func() time.Time { return now })
+
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Second)
+		_, _ = w.Write(make([]byte, 1<<20))
+		_ = w.Rate()
+	}
+	if got := w.Rate(); got != 1<<20 {
+		t.Errorf("\nwant rate: %d\n got rate: %v", 1<<20, got)
+	}
+	now = now.Add(3 * time.Second)
+	if got, want := w.Rate(), bytefmt.Rate(2<<20)/5; got != want {
+		t.Errorf("\nwant rate: %v\n got rate: %v", want, got)
+	}
+	now = now.Add(10 * time.Second)
+	if got := w.Rate(); got != 0 {
+		t.Errorf("\nwant rate: 0\n got rate: %v", got)
+	}
+	if got, want := w.AverageRate(), bytefmt.Rate(10<<20)/23; got != want {
+		t.Errorf("\nwant average rate: %v\n got average rate: %v", want, got)
+	}
+}
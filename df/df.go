@@ -0,0 +1,102 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package df reports file system capacity as the df utility does.
+package df
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// ErrNotSupported is returned by Stat on platforms without an implementation.
+var ErrNotSupported = errors.New("df: not supported on this platform")
+
+// Capacity is the capacity of a file system.
+type Capacity struct {
+	Total     bytefmt.Bytes
+	Free      bytefmt.Bytes // free for the superuser
+	Available bytefmt.Bytes // free for unprivileged users
+	Used      bytefmt.Bytes
+}
+
+// Percent returns the used percentage of the space available
+// to unprivileged users, 0 if there is no space.
+func (c Capacity) Percent() float64 {
+	total := float64(c.Used.Value) + float64(c.Available.Value)
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Used.Value) / total * 100
+}
+
+// use returns the used percentage as df prints it, rounded up.
+func (c Capacity) use() string {
+	total, carry := bits.Add64(c.Used.Value, c.Available.Value, 0)
+	if total == 0 && carry == 0 {
+		return "-"
+	}
+	hi, lo := bits.Mul64(c.Used.Value, 100)
+	if carry != 0 {
+		// Both are at least 2^63, drop a bit of precision.
+		hi, lo = bits.Mul64(c.Used.Value>>1, 100)
+		total = c.Used.Value>>1 + c.Available.Value>>1
+	}
+	p, r := bits.Div64(hi, lo, total)
+	if r != 0 {
+		p++
+	}
+	return strconv.FormatUint(p, 10) + "%"
+}
+
+// Entry is a row of a table.
+type Entry struct {
+	Filesystem string
+	MountedOn  string
+	Capacity
+}
+
+// WriteTable writes the entries as df -h does,
+// or as df -H does with powers of 1000 if si is true.
+func WriteTable(w io.Writer, entries []Entry, si bool) error {
+	human := bytefmt.Human
+	if si {
+		human = bytefmt.HumanSI
+	}
+	rows := [][]string{{"Filesystem", "Size", "Used", "Avail", "Use%", "Mounted on"}}
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Filesystem,
+			human(e.Total.Value),
+			human(e.Used.Value),
+			human(e.Available.Value),
+			e.use(),
+			e.MountedOn,
+		})
+	}
+	// Minimum widths and alignment of the columns of GNU df.
+	widths := []int{14, 5, 5, 5, 4, 0}
+	for _, r := range rows {
+		for i, s := range r {
+			if len(s) > widths[i] {
+				widths[i] = len(s)
+			}
+		}
+	}
+	var b strings.Builder
+	for _, r := range rows {
+		b.WriteString(r[0] + strings.Repeat(" ", widths[0]-len(r[0])))
+		for i := 1; i < 5; i++ {
+			b.WriteString(" " + strings.Repeat(" ", widths[i]-len(r[i])) + r[i])
+		}
+		b.WriteString(" " + r[5] + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package df_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+	"github.com/pfmt/bytefmt/df"
+)
+
+var entries = []df.Entry{
+	{
+		Filesystem: "/dev/vda",
+		MountedOn:  "/",
+		Capacity: df.Capacity{
+			Total:     bytefmt.New(270527852544),
+			Free:      bytefmt.New(250599325696),
+			Available: bytefmt.New(85660340224),
+			Used:      bytefmt.New(19928526848),
+		},
+	}, {
+		Filesystem: "tmpfs",
+		MountedOn:  "/dev/shm",
+		Capacity: df.Capacity{
+			Total:     bytefmt.New(64 << 20),
+			Free:      bytefmt.New(64 << 20),
+			Available: bytefmt.New(64 << 20),
+		},
+	}, {
+		Filesystem: "proc",
+		MountedOn:  "/proc",
+	}, {
+		Filesystem: "/dev/mapper/very-long-volume-name",
+		MountedOn:  "/srv/data",
+		Capacity: df.Capacity{
+			Total:     bytefmt.New(1 << 40),
+			Free:      bytefmt.New(1 << 38),
+			Available: bytefmt.New(1 << 38),
+			Used:      bytefmt.New(3 << 38),
+		},
+	},
+}
+
+var writeTableTests = []struct {
+	name string
+	line string
+	si   bool
+	want string
+}{
+	{
+		name: "powers of 1024",
+		line: testline(),
+		want: "Filesystem                         Size  Used Avail Use% Mounted on\n" +
+			"/dev/vda                           252G   19G   80G  19% /\n" +
+			"tmpfs                               64M     0   64M   0% /dev/shm\n" +
+			"proc                                  0     0     0    - /proc\n" +
+			"/dev/mapper/very-long-volume-name  1.0T  768G  256G  75% /srv/data\n",
+	}, {
+		name: "powers of 1000",
+		line: testline(),
+		si:   true,
+		want: "Filesystem                         Size  Used Avail Use% Mounted on\n" +
+			"/dev/vda                           271G   20G   86G  19% /\n" +
+			"tmpfs                               68M     0   68M   0% /dev/shm\n" +
+			"proc                                  0     0     0    - /proc\n" +
+			"/dev/mapper/very-long-volume-name  1.1T  825G  275G  75% /srv/data\n",
+	},
+}
+
+func TestWriteTable(t *testing.T) {
+	for _, tt := range writeTableTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got strings.Builder
+			if err := df.WriteTable(&got, entries, tt.si); err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if got.String() != tt.want {
+				t.Errorf("\nwant table:\n%s\n got table:\n%s\ntest: %s", tt.want, got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestCapacityPercent(t *testing.T) {
+	if got := entries[3].Percent(); got != 75 {
+		t.Errorf("\nwant percent: 75\n got percent: %v", got)
+	}
+	if got := entries[2].Percent(); got != 0 {
+		t.Errorf("\nwant percent: 0\n got percent: %v", got)
+	}
+}
+
+func testline() string {
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "it was not possible to recover file and line number information about function invocations"
+}
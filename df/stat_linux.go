@@ -0,0 +1,32 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package df
+
+import (
+	"io/fs"
+	"syscall"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// Stat returns the capacity of the file system containing the path.
+func Stat(path string) (Capacity, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Capacity{}, &fs.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	size := uint64(st.Frsize)
+	if size == 0 {
+		size = uint64(st.Bsize)
+	}
+	return Capacity{
+		Total:     bytefmt.New(st.Blocks * size),
+		Free:      bytefmt.New(st.Bfree * size),
+		Available: bytefmt.New(st.Bavail * size),
+		Used:      bytefmt.New((st.Blocks - st.Bfree) * size),
+	}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package df_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/pfmt/bytefmt/df"
+)
+
+func TestStat(t *testing.T) {
+	c, err := df.Stat("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total.Value == 0 || c.Used.Value+c.Free.Value != c.Total.Value || c.Available.Value > c.Free.Value {
+		t.Errorf("inconsistent capacity: %+v", c)
+	}
+}
+
+func TestStatNotExist(t *testing.T) {
+	_, err := df.Stat("/does/not/exist")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("\nwant error: %v\n got error: %v", fs.ErrNotExist, err)
+	}
+}
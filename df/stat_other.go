@@ -0,0 +1,12 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package df
+
+// Stat returns ErrNotSupported, it is implemented on Linux only.
+func Stat(path string) (Capacity, error) {
+	return Capacity{}, ErrNotSupported
+}
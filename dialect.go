@@ -0,0 +1,168 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the size syntax of a tool. All the dialects use
+// powers of 1024 whatever the spelling of their units of measure.
+type Dialect struct {
+	name  string
+	units []string // written by Format, the first one for bytes
+	unit  func(s string) (int, bool)
+	frac  bool // fractional numbers are allowed
+	round bool // fractional bytes are rounded rather than truncated
+	blank bool // blanks are allowed around the unit
+}
+
+var (
+	// JVM is the syntax of the options of the Java virtual machine
+	// such as -Xmx2g: an integer with an optional suffix k, m, g or t
+	// in either case.
+	JVM = &Dialect{
+		name:  "jvm",
+		units: []string{"", "k", "m", "g", "t"},
+		unit:  foldUnit("kmgt"),
+	}
+
+	// Docker is the syntax of the options of Docker such as --memory 512m
+	// as parsed by go-units: a number, possibly fractional, with
+	// an optional suffix k, m, g, t or p followed by optional i and b,
+	// in any case and possibly after a space. Fractional bytes are truncated.
+	Docker = &Dialect{
+		name:  "docker",
+		units: []string{"", "k", "m", "g", "t", "p"},
+		unit: func(s string) (int, bool) {
+			s = strings.ToLower(s)
+			s = strings.TrimSuffix(s, "b")
+			s = strings.TrimSuffix(s, "i")
+			return foldUnit("kmgtp")(s)
+		},
+		frac:  true,
+		blank: true,
+	}
+
+	// Nginx is the syntax of the directives of nginx
+	// such as client_max_body_size 10m: an integer with
+	// an optional suffix k, m or g in either case.
+	Nginx = &Dialect{
+		name:  "nginx",
+		units: []string{"", "k", "m", "g"},
+		unit:  foldUnit("kmg"),
+	}
+
+	// PostgreSQL is the syntax of the memory parameters of PostgreSQL
+	// such as shared_buffers = 128MB: a number, possibly fractional,
+	// with an optional case-sensitive unit B, kB, MB, GB or TB,
+	// all of them powers of 1024. Numbers without a unit are bytes
+	// here, whatever the base unit of the parameter.
+	// Fractional bytes are rounded.
+	PostgreSQL = &Dialect{
+		name:  "postgresql",
+		units: []string{"B", "kB", "MB", "GB", "TB"},
+		unit: func(s string) (int, bool) {
+			if s == "" {
+				return 0, true
+			}
+			p := indexString([]string{"B", "kB", "MB", "GB", "TB"}, s)
+			return p, p != -1
+		},
+		frac:  true,
+		round: true,
+		blank: true,
+	}
+)
+
+// foldUnit returns a function parsing the suffixes
+// for the powers of 1024 in either case.
+func foldUnit(suffixes string) func(string) (int, bool) {
+	return func(s string) (int, bool) {
+		if s == "" {
+			return 0, true
+		}
+		if len(s) != 1 {
+			return 0, false
+		}
+		p := strings.IndexByte(suffixes, s[0]|0x20)
+		return p + 1, p != -1
+	}
+}
+
+// String returns the name of the dialect.
+func (d *Dialect) String() string { return d.name }
+
+// Parse parses a size in the syntax of the dialect.
+func (d *Dialect) Parse(s string) (Bytes, error) {
+	v, err := d.parse(s)
+	if err != nil {
+		return Bytes{}, &ParseError{Input: s, Err: err}
+	}
+	return New(v), nil
+}
+
+func (d *Dialect) parse(s string) (uint64, error) {
+	if d.blank {
+		s = strings.TrimSpace(s)
+	}
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	whole, frac := s[:i], ""
+	if d.frac && i+1 < len(s) && s[i] == '.' && isDigit(s[i+1]) {
+		j := i + 1
+		for j < len(s) && isDigit(s[j]) {
+			j++
+		}
+		frac, i = s[i+1:j], j
+	}
+	unit := s[i:]
+	if d.blank {
+		unit = strings.TrimLeft(unit, " \t")
+	}
+	p, ok := d.unit(unit)
+	if whole == "" || !ok {
+		return 0, ErrSyntax
+	}
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil || w > math.MaxUint64>>(10*p) {
+		return 0, ErrRange
+	}
+	v := w << (10 * p)
+	if frac != "" {
+		if len(frac) > 19 {
+			frac = frac[:19]
+		}
+		f, _ := strconv.ParseUint(frac, 10, 64)
+		den := pow10(len(frac))
+		hi, lo := bits.Mul64(f, 1<<(10*p))
+		q, r := bits.Div64(hi, lo, den)
+		if d.round && r >= den-r {
+			q++
+		}
+		var carry uint64
+		if v, carry = bits.Add64(v, q, 0); carry != 0 {
+			return 0, ErrRange
+		}
+	}
+	return v, nil
+}
+
+// Format formats b in the syntax of the dialect
+// with the largest unit of measure dividing it exactly,
+// e.g. 2g for the JVM or 128MB for PostgreSQL.
+func (d *Dialect) Format(b Bytes) string {
+	v, p := b.Value, 0
+	for v != 0 && v%1024 == 0 && p+1 < len(d.units) {
+		v /= 1024
+		p++
+	}
+	return strconv.FormatUint(v, 10) + d.units[p]
+}
@@ -0,0 +1,252 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var dialectParseTests = []struct {
+	name    string
+	line    string
+	dialect *bytefmt.Dialect
+	in      string
+	want    uint64
+	err     error
+}{
+	{
+		name:    "jvm",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "2g",
+		want:    2 << 30,
+	}, {
+		name:    "jvm upper case",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "512M",
+		want:    512 << 20,
+	}, {
+		name:    "jvm bytes",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "1048576",
+		want:    1 << 20,
+	}, {
+		name:    "jvm fraction",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "1.5g",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "jvm iec",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "2gb",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "jvm blank",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "2 g",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "docker",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "512m",
+		want:    512 << 20,
+	}, {
+		name:    "docker spellings",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "1 GiB",
+		want:    1 << 30,
+	}, {
+		name:    "docker mb is binary",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "10MB",
+		want:    10 << 20,
+	}, {
+		name:    "docker fraction truncated",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "1.0001k",
+		want:    1024,
+	}, {
+		name:    "docker peta",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "1.5p",
+		want:    3 << 49,
+	}, {
+		name:    "docker bytes",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "100b",
+		want:    100,
+	}, {
+		name:    "docker bits",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "1kbit",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "nginx",
+		line:    testline(),
+		dialect: bytefmt.Nginx,
+		in:      "10m",
+		want:    10 << 20,
+	}, {
+		name:    "nginx giga",
+		line:    testline(),
+		dialect: bytefmt.Nginx,
+		in:      "1G",
+		want:    1 << 30,
+	}, {
+		name:    "nginx tera",
+		line:    testline(),
+		dialect: bytefmt.Nginx,
+		in:      "1t",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "postgresql",
+		line:    testline(),
+		dialect: bytefmt.PostgreSQL,
+		in:      "128MB",
+		want:    128 << 20,
+	}, {
+		name:    "postgresql blank",
+		line:    testline(),
+		dialect: bytefmt.PostgreSQL,
+		in:      " 4 GB ",
+		want:    4 << 30,
+	}, {
+		name:    "postgresql fraction rounded",
+		line:    testline(),
+		dialect: bytefmt.PostgreSQL,
+		in:      "1.0005kB",
+		want:    1025,
+	}, {
+		name:    "postgresql case-sensitive",
+		line:    testline(),
+		dialect: bytefmt.PostgreSQL,
+		in:      "128mb",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "postgresql no binary prefix",
+		line:    testline(),
+		dialect: bytefmt.PostgreSQL,
+		in:      "1KB",
+		err:     bytefmt.ErrSyntax,
+	}, {
+		name:    "out of range",
+		line:    testline(),
+		dialect: bytefmt.JVM,
+		in:      "16777216t",
+		err:     bytefmt.ErrRange,
+	}, {
+		name:    "empty",
+		line:    testline(),
+		dialect: bytefmt.Docker,
+		in:      "m",
+		err:     bytefmt.ErrSyntax,
+	},
+}
+
+func TestDialectParse(t *testing.T) {
+	for _, tt := range dialectParseTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := tt.dialect.Parse(tt.in)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if b.Value != tt.want {
+				t.Errorf("\nwant bytes: %d\n got bytes: %d\ntest: %s", tt.want, b.Value, tt.line)
+			}
+		})
+	}
+}
+
+var dialectFormatTests = []struct {
+	line  string
+	bytes uint64
+	jvm   string
+	dock  string
+	nginx string
+	pg    string
+}{
+	{
+		line:  testline(),
+		bytes: 0,
+		jvm:   "0",
+		dock:  "0",
+		nginx: "0",
+		pg:    "0B",
+	}, {
+		line:  testline(),
+		bytes: 1000,
+		jvm:   "1000",
+		dock:  "1000",
+		nginx: "1000",
+		pg:    "1000B",
+	}, {
+		line:  testline(),
+		bytes: 1536 << 20,
+		jvm:   "1536m",
+		dock:  "1536m",
+		nginx: "1536m",
+		pg:    "1536MB",
+	}, {
+		line:  testline(),
+		bytes: 2 << 30,
+		jvm:   "2g",
+		dock:  "2g",
+		nginx: "2g",
+		pg:    "2GB",
+	}, {
+		line:  testline(),
+		bytes: 1 << 50,
+		jvm:   "1024t",
+		dock:  "1p",
+		nginx: "1048576g",
+		pg:    "1024TB",
+	},
+}
+
+func TestDialectFormat(t *testing.T) {
+	for _, tt := range dialectFormatTests {
+		tt := tt
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+
+			b := bytefmt.New(tt.bytes)
+			for _, c := range []struct {
+				d    *bytefmt.Dialect
+				want string
+			}{
+				{bytefmt.JVM, tt.jvm},
+				{bytefmt.Docker, tt.dock},
+				{bytefmt.Nginx, tt.nginx},
+				{bytefmt.PostgreSQL, tt.pg},
+			} {
+				got := c.d.Format(b)
+				if got != c.want {
+					t.Errorf("\nwant %s: %#v\n got %s: %#v\ntest: %s", c.d, c.want, c.d, got, tt.line)
+				}
+				if r, err := c.d.Parse(got); err != nil || r.Value != tt.bytes {
+					t.Errorf("\nround trip of %s %s: %d, %v\ntest: %s", c.d, got, r.Value, err, tt.line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package du estimates file space usage of directory trees
+// as the du utility does.
+package du
+
+import (
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// Options control Walk.
+type Options struct {
+	// Exclude skips files and directories whose name or path
+	// matches any of the path.Match patterns.
+	Exclude []string
+	// MaxDepth limits the depth of directories in the tree,
+	// the root is at depth 0 so that zero keeps only the root
+	// as du --max-depth=0 does; unlimited if nil.
+	// Deeper directories are still counted in their ancestors.
+	MaxDepth *int
+}
+
+// Dir is the space usage of a directory and its contents.
+type Dir struct {
+	Path string
+	// Apparent is the sum of file sizes.
+	Apparent bytefmt.Bytes
+	// Allocated is the sum of disk space allocated to files,
+	// it equals Apparent if the file system does not report blocks.
+	Allocated bytefmt.Bytes
+	Dirs      []*Dir
+}
+
+// Walk computes the space usage of the directory root of the fsys.
+// Files with several hard links are counted once when the file system
+// reports inode numbers.
+func Walk(fsys fs.FS, root string, opts *Options) (*Dir, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	fi, err := fs.Stat(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	w := &walker{fsys: fsys, opts: opts, seen: make(map[fileID]bool)}
+	d := &Dir{Path: root}
+	w.add(d, fi)
+	if fi.IsDir() {
+		if err := w.walk(d, root, 0); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+type walker struct {
+	fsys fs.FS
+	opts *Options
+	seen map[fileID]bool
+}
+
+func (w *walker) walk(d *Dir, dir string, depth int) error {
+	entries, err := fs.ReadDir(w.fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := path.Join(dir, e.Name())
+		if w.excluded(name) {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if !e.IsDir() {
+			w.add(d, fi)
+			continue
+		}
+		sub := &Dir{Path: name}
+		w.add(sub, fi)
+		if err := w.walk(sub, name, depth+1); err != nil {
+			return err
+		}
+		d.Apparent = d.Apparent.AddSat(sub.Apparent)
+		d.Allocated = d.Allocated.AddSat(sub.Allocated)
+		if w.opts.MaxDepth == nil || depth < *w.opts.MaxDepth {
+			d.Dirs = append(d.Dirs, sub)
+		}
+	}
+	return nil
+}
+
+func (w *walker) excluded(name string) bool {
+	for _, p := range w.opts.Exclude {
+		if ok, _ := path.Match(p, path.Base(name)); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// add counts the file in d unless it is a hard link already counted.
+func (w *walker) add(d *Dir, fi fs.FileInfo) {
+	size := uint64(0)
+	if fi.Size() > 0 {
+		size = uint64(fi.Size())
+	}
+	allocated := size
+	if s, ok := stat(fi); ok {
+		if s.links > 1 {
+			if w.seen[s.id] {
+				return
+			}
+			w.seen[s.id] = true
+		}
+		allocated = s.allocated
+	}
+	d.Apparent = d.Apparent.AddSat(bytefmt.New(size))
+	d.Allocated = d.Allocated.AddSat(bytefmt.New(allocated))
+}
+
+// WriteTo writes the allocated sizes of the tree as du -h does,
+// subdirectories before their parents.
+func (d *Dir) WriteTo(w io.Writer) (int64, error) {
+	return d.write(w, false)
+}
+
+// WriteApparentTo writes the apparent sizes of the tree
+// as du -h --apparent-size does.
+func (d *Dir) WriteApparentTo(w io.Writer) (int64, error) {
+	return d.write(w, true)
+}
+
+func (d *Dir) write(w io.Writer, apparent bool) (int64, error) {
+	var n int64
+	for _, sub := range d.Dirs {
+		m, err := sub.write(w, apparent)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	size := d.Allocated
+	if apparent {
+		size = d.Apparent
+	}
+	m, err := io.WriteString(w, bytefmt.Human(size.Value)+"\t"+d.Path+"\n")
+	return n + int64(m), err
+}
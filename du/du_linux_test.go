@@ -0,0 +1,45 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package du_test
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pfmt/bytefmt/du"
+)
+
+func TestWalkBlocksAndHardLinks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"small":    {Data: make([]byte, 10), Sys: &syscall.Stat_t{Ino: 1, Nlink: 1, Blocks: 8}},
+		"a/big":    {Data: make([]byte, 1<<20), Sys: &syscall.Stat_t{Ino: 2, Nlink: 2, Blocks: 2048}},
+		"b/big":    {Data: make([]byte, 1<<20), Sys: &syscall.Stat_t{Ino: 2, Nlink: 2, Blocks: 2048}},
+		"b/sparse": {Data: make([]byte, 1<<20), Sys: &syscall.Stat_t{Ino: 3, Nlink: 1, Blocks: 16}},
+	}
+	d, err := du.Walk(fsys, ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got strings.Builder
+	_, _ = d.WriteTo(&got)
+	want := "1.0M\ta\n" +
+		"8.0K\tb\n" +
+		"1.1M\t.\n"
+	if got.String() != want {
+		t.Errorf("\nwant du:\n%s\n got du:\n%s", want, got.String())
+	}
+
+	got.Reset()
+	_, _ = d.WriteApparentTo(&got)
+	want = "1.0M\ta\n" +
+		"1.0M\tb\n" +
+		"2.1M\t.\n"
+	if got.String() != want {
+		t.Errorf("\nwant du --apparent-size:\n%s\n got du --apparent-size:\n%s", want, got.String())
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package du_test
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pfmt/bytefmt/du"
+)
+
+var testFS = fstest.MapFS{
+	"a.txt":           {Data: make([]byte, 1000)},
+	"docs/readme.md":  {Data: make([]byte, 3000)},
+	"docs/img/a.png":  {Data: make([]byte, 1<<20)},
+	"docs/img/b.png":  {Data: make([]byte, 1<<19)},
+	"src/main.go":     {Data: make([]byte, 5000)},
+	"src/main.o":      {Data: make([]byte, 1<<21)},
+	"src/vendor/x.go": {Data: make([]byte, 100)},
+	"empty":           {Mode: fs.ModeDir},
+}
+
+var walkTests = []struct {
+	name string
+	line string
+	root string
+	opts *du.Options
+	want string
+}{
+	{
+		name: "all",
+		line: testline(),
+		root: ".",
+		want: "1.5M\tdocs/img\n" +
+			"1.6M\tdocs\n" +
+			"0\tempty\n" +
+			"100\tsrc/vendor\n" +
+			"2.1M\tsrc\n" +
+			"3.6M\t.\n",
+	}, {
+		name: "subdirectory",
+		line: testline(),
+		root: "docs",
+		want: "1.5M\tdocs/img\n" +
+			"1.6M\tdocs\n",
+	}, {
+		name: "exclude names",
+		line: testline(),
+		root: ".",
+		opts: &du.Options{Exclude: []string{"*.o", "img"}},
+		want: "3.0K\tdocs\n" +
+			"0\tempty\n" +
+			"100\tsrc/vendor\n" +
+			"5.0K\tsrc\n" +
+			"8.9K\t.\n",
+	}, {
+		name: "exclude paths",
+		line: testline(),
+		root: ".",
+		opts: &du.Options{Exclude: []string{"src/*"}},
+		want: "1.5M\tdocs/img\n" +
+			"1.6M\tdocs\n" +
+			"0\tempty\n" +
+			"0\tsrc\n" +
+			"1.6M\t.\n",
+	}, {
+		name: "max depth",
+		line: testline(),
+		root: ".",
+		opts: &du.Options{MaxDepth: maxDepth(1)},
+		want: "1.6M\tdocs\n" +
+			"0\tempty\n" +
+			"2.1M\tsrc\n" +
+			"3.6M\t.\n",
+	}, {
+		name: "max depth zero",
+		line: testline(),
+		root: ".",
+		opts: &du.Options{MaxDepth: maxDepth(0)},
+		want: "3.6M\t.\n",
+	}, {
+		name: "file",
+		line: testline(),
+		root: "a.txt",
+		want: "1000\ta.txt\n",
+	},
+}
+
+func TestWalk(t *testing.T) {
+	for _, tt := range walkTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := du.Walk(testFS, tt.root, tt.opts)
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			var got strings.Builder
+			if _, err := d.WriteApparentTo(&got); err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if got.String() != tt.want {
+				t.Errorf("\nwant du:\n%s\n got du:\n%s\ntest: %s", tt.want, got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestWalkTotals(t *testing.T) {
+	d, err := du.Walk(testFS, ".", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := uint64(1000 + 3000 + 1<<20 + 1<<19 + 5000 + 1<<21 + 100)
+	if d.Apparent.Value != want || d.Allocated.Value != want {
+		t.Errorf("\nwant totals: %d %d\n got totals: %d %d", want, want, d.Apparent.Value, d.Allocated.Value)
+	}
+	if len(d.Dirs) != 3 || d.Dirs[0].Path != "docs" || len(d.Dirs[0].Dirs) != 1 {
+		t.Errorf("unexpected tree: %+v", d.Dirs)
+	}
+}
+
+func TestWalkNotExist(t *testing.T) {
+	if _, err := du.Walk(testFS, "missing", nil); err == nil {
+		t.Error("want error")
+	}
+}
+
+func maxDepth(n int) *int { return &n }
+
+func testline() string {
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "it was not possible to recover file and line number information about function invocations"
+}
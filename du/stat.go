@@ -0,0 +1,15 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package du
+
+type fileID struct {
+	dev, ino uint64
+}
+
+type fileStat struct {
+	id        fileID
+	links     uint64
+	allocated uint64 // bytes in allocated blocks
+}
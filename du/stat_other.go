@@ -0,0 +1,13 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package du
+
+import "io/fs"
+
+func stat(fi fs.FileInfo) (fileStat, bool) {
+	return fileStat{}, false
+}
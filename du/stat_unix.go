@@ -0,0 +1,24 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package du
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+func stat(fi fs.FileInfo) (fileStat, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileStat{}, false
+	}
+	return fileStat{
+		id:        fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)},
+		links:     uint64(st.Nlink),
+		allocated: uint64(st.Blocks) * 512,
+	}, true
+}
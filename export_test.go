@@ -4,6 +4,8 @@
 
 package bytefmt
 
+import "time"
+
 // Exported for testing only.
 
 func (b Bytes) Kilobytes() float64 { return b.kilobytes() }
@@ -12,3 +14,16 @@ func (b Bytes) Gigabytes() float64 { return b.gigabytes() }
 func (b Bytes) Terabytes() float64 { return b.terabytes() }
 func (b Bytes) Petabytes() float64 { return b.petabytes() }
 func (b Bytes) Exabytes() float64  { return b.exabytes() }
+
+func (c *counter) SetClock(now func() time.Time) {
+	c.now = now
+	c.start = now()
+}
+
+func (c *counter) RateSamples() int {
+	c.rate.mu.Lock()
+	defer c.rate.mu.Unlock()
+	c.rate.w.mu.Lock()
+	defer c.rate.w.mu.Unlock()
+	return len(c.rate.w.samples)
+}
@@ -0,0 +1,433 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ExprError describes a problem evaluating an expression.
+type ExprError struct {
+	Expr   string
+	Offset int
+	Msg    string
+	Err    error // ErrSyntax, ErrRange, ErrOverflow, ErrUnderflow or ErrDivisionByZero
+}
+
+func (e *ExprError) Error() string {
+	return "bytefmt: expression " + strconv.Quote(e.Expr) + ": " + e.Msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+func (e *ExprError) Unwrap() error { return e.Err }
+
+// Eval evaluates an expression of sizes such as "2G + 512M",
+// "75% of 8G" or "max(1G, 10% of total)" with the variables.
+//
+// The sizes are those accepted by Parse, numbers without a unit
+// are plain numbers which may multiply or divide sizes,
+// dividing a size by a size gives a plain number.
+// Integers count bytes when added to or compared with sizes.
+// A percentage N% is the number N/100, "N% of X" is N/100*X.
+// The operators are +, -, * and / with the usual precedence,
+// parentheses and the functions min and max of one or more arguments.
+// The arithmetic is exact except that sizes are rounded to whole bytes,
+// it fails on overflow and on negative results.
+func Eval(expr string, vars map[string]Bytes) (Bytes, error) {
+	p := &exprParser{s: expr, vars: vars}
+	v, err := p.expr()
+	if err == nil && p.skip() < len(expr) {
+		err = p.errorf(p.pos, ErrSyntax, "unexpected "+p.token())
+	}
+	if err != nil {
+		return Bytes{}, err
+	}
+	if v.size {
+		return New(v.b), nil
+	}
+	q, r := v.num/v.den, v.num%v.den
+	if r >= v.den-r {
+		q++ // the rounded value fits as den > 1
+	}
+	return New(q), nil
+}
+
+// exprValue is a size or a plain number num/den.
+type exprValue struct {
+	size     bool
+	b        uint64
+	num, den uint64
+}
+
+// asSize returns the value in bytes if it is a size or an integer.
+func (v exprValue) asSize() (uint64, bool) {
+	if v.size {
+		return v.b, true
+	}
+	return v.num, v.den == 1
+}
+
+type exprParser struct {
+	s    string
+	pos  int
+	vars map[string]Bytes
+}
+
+func (p *exprParser) errorf(off int, err error, msg string) error {
+	return &ExprError{Expr: p.s, Offset: off, Msg: msg, Err: err}
+}
+
+// skip skips blanks and returns the position.
+func (p *exprParser) skip() int {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+	return p.pos
+}
+
+// token returns a description of the next token for errors.
+func (p *exprParser) token() string {
+	if p.skip() == len(p.s) {
+		return "end of expression"
+	}
+	return strconv.QuoteRune(rune(p.s[p.pos]))
+}
+
+// ident returns the identifier at the position without consuming it.
+func (p *exprParser) ident() string {
+	i := p.skip()
+	j := i
+	for j < len(p.s) && (isLetter(p.s[j]) || p.s[j] == '_' || j > i && isDigit(p.s[j])) {
+		j++
+	}
+	return p.s[i:j]
+}
+
+// expr = term { ("+" | "-") term }.
+func (p *exprParser) expr() (exprValue, error) {
+	x, err := p.term()
+	for err == nil {
+		off := p.skip()
+		if off == len(p.s) || p.s[off] != '+' && p.s[off] != '-' {
+			break
+		}
+		p.pos++
+		var y exprValue
+		if y, err = p.term(); err != nil {
+			break
+		}
+		x, err = p.addSub(off, x, y)
+	}
+	return x, err
+}
+
+// term = unary { ("*" | "/") unary }.
+func (p *exprParser) term() (exprValue, error) {
+	x, err := p.unary()
+	for err == nil {
+		off := p.skip()
+		if off == len(p.s) || p.s[off] != '*' && p.s[off] != '/' {
+			break
+		}
+		p.pos++
+		var y exprValue
+		if y, err = p.unary(); err != nil {
+			break
+		}
+		if p.s[off] == '*' {
+			x, err = p.mul(off, x, y)
+		} else {
+			x, err = p.div(off, x, y)
+		}
+	}
+	return x, err
+}
+
+// unary = primary [ "%" [ "of" unary ] ].
+func (p *exprParser) unary() (exprValue, error) {
+	x, err := p.primary()
+	if err != nil {
+		return x, err
+	}
+	off := p.skip()
+	if off == len(p.s) || p.s[off] != '%' {
+		return x, nil
+	}
+	p.pos++
+	if x.size {
+		return x, p.errorf(off, ErrSyntax, "percentage of a size")
+	}
+	if x.den, err = p.mulChecked(off, x.den, 100); err != nil {
+		return x, err
+	}
+	x = reduce(x)
+	if p.ident() != "of" {
+		return x, nil
+	}
+	of := p.skip()
+	p.pos += len("of")
+	y, err := p.unary()
+	if err != nil {
+		return x, err
+	}
+	return p.mul(of, x, y)
+}
+
+// primary = number [ unit ] | variable | function "(" expr { "," expr } ")" | "(" expr ")".
+func (p *exprParser) primary() (exprValue, error) {
+	off := p.skip()
+	if off == len(p.s) {
+		return exprValue{}, p.errorf(off, ErrSyntax, "unexpected end of expression")
+	}
+	c := p.s[off]
+	switch {
+	case c == '(':
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return x, err
+		}
+		if p.skip() == len(p.s) || p.s[p.pos] != ')' {
+			return x, p.errorf(p.pos, ErrSyntax, "expected ) instead of "+p.token())
+		}
+		p.pos++
+		return x, nil
+	case isDigit(c) || c == '.':
+		return p.number()
+	case isLetter(c) || c == '_':
+		name := p.ident()
+		p.pos += len(name)
+		if name == "min" || name == "max" {
+			return p.call(off, name)
+		}
+		b, ok := p.vars[name]
+		if !ok {
+			return exprValue{}, p.errorf(off, ErrSyntax, "undefined variable "+name)
+		}
+		return exprValue{size: true, b: b.Value}, nil
+	}
+	return exprValue{}, p.errorf(off, ErrSyntax, "unexpected "+p.token())
+}
+
+// number parses a number with an optional unit of measure.
+func (p *exprParser) number() (exprValue, error) {
+	off := p.pos
+	i := off
+	for i < len(p.s) && (isDigit(p.s[i]) || p.s[i] == '.') {
+		i++
+	}
+	num := p.s[off:i]
+	p.pos = i
+	if unit := p.ident(); unit != "" {
+		if _, ok := parseUnit(unit); ok {
+			p.pos += len(unit)
+			v, err := parse(num + unit)
+			if err != nil {
+				return exprValue{}, p.errorf(off, err, "invalid size "+strconv.Quote(p.s[off:p.pos]))
+			}
+			return exprValue{size: true, b: v}, nil
+		}
+	}
+	whole, frac := num, ""
+	if j := strings.IndexByte(num, '.'); j != -1 {
+		whole, frac = num[:j], num[j+1:]
+	}
+	if whole+frac == "" || strings.IndexByte(frac, '.') != -1 {
+		return exprValue{}, p.errorf(off, ErrSyntax, "invalid number "+strconv.Quote(num))
+	}
+	n, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil || len(frac) > 19 {
+		return exprValue{}, p.errorf(off, ErrRange, "number "+strconv.Quote(num)+" out of range")
+	}
+	return reduce(exprValue{num: n, den: pow10(len(frac))}), nil
+}
+
+// call parses the arguments of the function min or max.
+func (p *exprParser) call(off int, name string) (exprValue, error) {
+	if p.skip() == len(p.s) || p.s[p.pos] != '(' {
+		return exprValue{}, p.errorf(p.pos, ErrSyntax, "expected ( after "+name+" instead of "+p.token())
+	}
+	p.pos++
+	var args []exprValue
+	for {
+		x, err := p.expr()
+		if err != nil {
+			return x, err
+		}
+		args = append(args, x)
+		if p.skip() < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		return x, p.errorf(p.pos, ErrSyntax, "expected , or ) instead of "+p.token())
+	}
+	r := args[0]
+	for _, x := range args[1:] {
+		c, ok := compare(r, x)
+		if !ok {
+			return r, p.errorf(off, ErrSyntax, name+" of sizes and fractions")
+		}
+		if name == "min" && c > 0 || name == "max" && c < 0 {
+			r = x
+		}
+	}
+	return r, nil
+}
+
+// compare compares x and y if both are numbers or sizes.
+func compare(x, y exprValue) (int, bool) {
+	if !x.size && !y.size {
+		xh, xl := bits.Mul64(x.num, y.den)
+		yh, yl := bits.Mul64(y.num, x.den)
+		switch {
+		case xh < yh || xh == yh && xl < yl:
+			return -1, true
+		case xh == yh && xl == yl:
+			return 0, true
+		}
+		return 1, true
+	}
+	a, ok := x.asSize()
+	b, ok2 := y.asSize()
+	if !ok || !ok2 {
+		return 0, false
+	}
+	switch {
+	case a < b:
+		return -1, true
+	case a == b:
+		return 0, true
+	}
+	return 1, true
+}
+
+func (p *exprParser) addSub(off int, x, y exprValue) (exprValue, error) {
+	add := p.s[off] == '+'
+	if !x.size && !y.size {
+		// a/b ± c/d = (ad ± cb) / bd
+		ad, err := p.mulChecked(off, x.num, y.den)
+		if err != nil {
+			return x, err
+		}
+		cb, err := p.mulChecked(off, y.num, x.den)
+		if err != nil {
+			return x, err
+		}
+		bd, err := p.mulChecked(off, x.den, y.den)
+		if err != nil {
+			return x, err
+		}
+		var n, carry uint64
+		if add {
+			n, carry = bits.Add64(ad, cb, 0)
+		} else {
+			n, carry = bits.Sub64(ad, cb, 0)
+		}
+		if carry != 0 {
+			return x, p.arithError(off, add)
+		}
+		return reduce(exprValue{num: n, den: bd}), nil
+	}
+	a, ok := x.asSize()
+	b, ok2 := y.asSize()
+	if !ok || !ok2 {
+		return x, p.errorf(off, ErrSyntax, "cannot add or subtract a size and a fraction")
+	}
+	var v Bytes
+	var err error
+	if add {
+		v, err = New(a).Add(New(b))
+	} else {
+		v, err = New(a).Sub(New(b))
+	}
+	if err != nil {
+		return x, p.arithError(off, add)
+	}
+	return exprValue{size: true, b: v.Value}, nil
+}
+
+func (p *exprParser) mul(off int, x, y exprValue) (exprValue, error) {
+	switch {
+	case x.size && y.size:
+		return x, p.errorf(off, ErrSyntax, "cannot multiply sizes")
+	case x.size:
+		return p.scale(off, x, y.num, y.den)
+	case y.size:
+		return p.scale(off, y, x.num, x.den)
+	}
+	n, err := p.mulChecked(off, x.num, y.num)
+	if err != nil {
+		return x, err
+	}
+	d, err := p.mulChecked(off, x.den, y.den)
+	if err != nil {
+		return x, err
+	}
+	return reduce(exprValue{num: n, den: d}), nil
+}
+
+func (p *exprParser) div(off int, x, y exprValue) (exprValue, error) {
+	if y.size && y.b == 0 || !y.size && y.num == 0 {
+		return x, p.errorf(off, ErrDivisionByZero, "division by zero")
+	}
+	switch {
+	case x.size && y.size:
+		return reduce(exprValue{num: x.b, den: y.b}), nil
+	case x.size:
+		return p.scale(off, x, y.den, y.num)
+	case y.size:
+		return x, p.errorf(off, ErrSyntax, "cannot divide a number by a size")
+	}
+	return p.mul(off, x, exprValue{num: y.den, den: y.num})
+}
+
+// scale returns the size x multiplied by num/den rounded to whole bytes.
+func (p *exprParser) scale(off int, x exprValue, num, den uint64) (exprValue, error) {
+	hi, lo := bits.Mul64(x.b, num)
+	if hi >= den {
+		return x, p.errorf(off, ErrOverflow, "size overflow")
+	}
+	q, r := bits.Div64(hi, lo, den)
+	if r >= den-r {
+		if q == 1<<64-1 {
+			return x, p.errorf(off, ErrOverflow, "size overflow")
+		}
+		q++
+	}
+	return exprValue{size: true, b: q}, nil
+}
+
+func (p *exprParser) mulChecked(off int, a, b uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return 0, p.errorf(off, ErrOverflow, "number overflow")
+	}
+	return lo, nil
+}
+
+func (p *exprParser) arithError(off int, add bool) error {
+	if add {
+		return p.errorf(off, ErrOverflow, "size overflow")
+	}
+	return p.errorf(off, ErrUnderflow, "negative result")
+}
+
+// reduce reduces the fraction of a number.
+func reduce(x exprValue) exprValue {
+	a, b := x.num, x.den
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a > 1 {
+		x.num /= a
+		x.den /= a
+	}
+	return x
+}
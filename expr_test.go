@@ -0,0 +1,175 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var evalTests = []struct {
+	name string
+	line string
+	expr string
+	vars map[string]bytefmt.Bytes
+	want uint64
+	err  error
+	msg  string
+}{
+	{
+		name: "sum",
+		line: testline(),
+		expr: "2G + 512M",
+		want: 2<<30 + 512<<20,
+	}, {
+		name: "percentage",
+		line: testline(),
+		expr: "75% of 8G",
+		want: 6 << 30,
+	}, {
+		name: "max",
+		line: testline(),
+		expr: "max(1G, 10% of total)",
+		vars: map[string]bytefmt.Bytes{"total": bytefmt.New(16 << 30)},
+		want: 1717986918,
+	}, {
+		name: "min",
+		line: testline(),
+		expr: "min(4G, total - 512M, 3.5GiB)",
+		vars: map[string]bytefmt.Bytes{"total": bytefmt.New(4 << 30)},
+		want: 3584 << 20,
+	}, {
+		name: "precedence",
+		line: testline(),
+		expr: "1G + 2 * 512M - 1G / 4",
+		want: 1792 << 20,
+	}, {
+		name: "parentheses",
+		line: testline(),
+		expr: "(1G + 1G) * 3 / 2",
+		want: 3 << 30,
+	}, {
+		name: "fractions",
+		line: testline(),
+		expr: "8G * 1.5 / 3",
+		want: 4 << 30,
+	}, {
+		name: "exact percentage of percentage",
+		line: testline(),
+		expr: "50% of 50% of 1K",
+		want: 256,
+	}, {
+		name: "ratio of sizes",
+		line: testline(),
+		expr: "4G / 1G * 100M",
+		want: 400 << 20,
+	}, {
+		name: "integers count bytes",
+		line: testline(),
+		expr: "1K + 24",
+		want: 1048,
+	}, {
+		name: "plain number",
+		line: testline(),
+		expr: "10 / 4",
+		want: 3,
+	}, {
+		name: "unit after a blank and si",
+		line: testline(),
+		expr: "1.5 GB - 500 MB",
+		want: 1000000000,
+	}, {
+		name: "syntax",
+		line: testline(),
+		expr: "2G +",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "2G +": unexpected end of expression at offset 4`,
+	}, {
+		name: "unclosed",
+		line: testline(),
+		expr: "(1G + 2G",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "(1G + 2G": expected ) instead of end of expression at offset 8`,
+	}, {
+		name: "trailing",
+		line: testline(),
+		expr: "1G 2G",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "1G 2G": unexpected '2' at offset 3`,
+	}, {
+		name: "undefined variable",
+		line: testline(),
+		expr: "10% of total",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "10% of total": undefined variable total at offset 7`,
+	}, {
+		name: "multiply sizes",
+		line: testline(),
+		expr: "1G * 1G",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "1G * 1G": cannot multiply sizes at offset 3`,
+	}, {
+		name: "size and fraction",
+		line: testline(),
+		expr: "1G + 0.5",
+		err:  bytefmt.ErrSyntax,
+		msg:  `bytefmt: expression "1G + 0.5": cannot add or subtract a size and a fraction at offset 3`,
+	}, {
+		name: "overflow",
+		line: testline(),
+		expr: "8E + 8E",
+		err:  bytefmt.ErrOverflow,
+		msg:  `bytefmt: expression "8E + 8E": size overflow at offset 3`,
+	}, {
+		name: "multiplication overflow",
+		line: testline(),
+		expr: "1 + 4E * 4",
+		err:  bytefmt.ErrOverflow,
+		msg:  `bytefmt: expression "1 + 4E * 4": size overflow at offset 7`,
+	}, {
+		name: "negative",
+		line: testline(),
+		expr: "1G - 2G",
+		err:  bytefmt.ErrUnderflow,
+		msg:  `bytefmt: expression "1G - 2G": negative result at offset 3`,
+	}, {
+		name: "division by zero",
+		line: testline(),
+		expr: "1G / (1 - 1)",
+		err:  bytefmt.ErrDivisionByZero,
+		msg:  `bytefmt: expression "1G / (1 - 1)": division by zero at offset 3`,
+	}, {
+		name: "size out of range",
+		line: testline(),
+		expr: "max(1G, 17E)",
+		err:  bytefmt.ErrRange,
+		msg:  `bytefmt: expression "max(1G, 17E)": invalid size "17E" at offset 8`,
+	},
+}
+
+func TestEval(t *testing.T) {
+	for _, tt := range evalTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := bytefmt.Eval(tt.expr, tt.vars)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if err != nil {
+				if err.Error() != tt.msg {
+					t.Errorf("\nwant message: %s\n got message: %s\ntest: %s", tt.msg, err, tt.line)
+				}
+				return
+			}
+			if b.Value != tt.want {
+				t.Errorf("\nwant value: %d\n got value: %d\ntest: %s", tt.want, b.Value, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FilterOptions control Filter.
+type FilterOptions struct {
+	// Fields are the 1-based numbers of the fields to humanize,
+	// all fields if empty.
+	Fields []int
+	// Delimiter separates the fields,
+	// fields are separated by runs of blanks if empty.
+	Delimiter string
+	// Format formats the sizes, e.g. "% .1f", see Bytes.Format;
+	// a number with at most one decimal such as 1.5G if empty.
+	Format string
+	// SI formats the sizes in powers of 1000, see NewSI.
+	SI bool
+	// Names are the names of the units of measure, see Bytes.Names.
+	Names []string
+	// Padding pads the sizes with spaces to the width,
+	// on the right if negative. If zero, fields separated by blanks
+	// keep their original width and others are not padded.
+	Padding int
+	// Header is the number of lines at the start copied unchanged.
+	Header int
+}
+
+// Filter copies lines from r to w, replacing the numbers of bytes
+// in the selected fields with sizes, as numfmt --field does.
+// Fields which are not integers are copied unchanged.
+func Filter(w io.Writer, r io.Reader, opts *FilterOptions) error {
+	if opts == nil {
+		opts = &FilterOptions{}
+	}
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for n := 0; ; n++ {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if n >= opts.Header {
+				line = opts.line(line)
+			}
+			if _, err := bw.WriteString(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// line returns the line with the selected fields humanized.
+func (o *FilterOptions) line(line string) string {
+	end := len(line)
+	if strings.HasSuffix(line[:end], "\n") {
+		end--
+	}
+	if strings.HasSuffix(line[:end], "\r") {
+		end--
+	}
+	s, eol := line[:end], line[end:]
+	var b strings.Builder
+	if o.Delimiter != "" {
+		for i, f := range strings.Split(s, o.Delimiter) {
+			if i > 0 {
+				b.WriteString(o.Delimiter)
+			}
+			if h, ok := o.field(i+1, f); ok {
+				f = o.pad(h, 0)
+			}
+			b.WriteString(f)
+		}
+		return b.String() + eol
+	}
+	for i, field := 0, 1; i < len(s); field++ {
+		j := i
+		for j < len(s) && isBlank(s[j]) {
+			j++
+		}
+		k := j
+		for k < len(s) && !isBlank(s[k]) {
+			k++
+		}
+		if k == j {
+			b.WriteString(s[i:])
+			break
+		}
+		h, ok := o.field(field, s[j:k])
+		if !ok {
+			b.WriteString(s[i:k])
+			i = k
+			continue
+		}
+		sep := s[i:j]
+		if o.Padding == 0 {
+			// Right-align in the width of the field and its blanks,
+			// keeping a blank between fields.
+			h = o.pad(h, k-i)
+			if i > 0 && h[0] != ' ' {
+				h = sep[:1] + h
+			}
+			b.WriteString(h)
+		} else {
+			b.WriteString(sep + o.pad(h, 0))
+		}
+		i = k
+	}
+	return b.String() + eol
+}
+
+// field returns the field number n humanized
+// or false if it is not selected or is not an integer.
+func (o *FilterOptions) field(n int, s string) (string, bool) {
+	if len(o.Fields) > 0 {
+		selected := false
+		for _, f := range o.Fields {
+			if f == n {
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			return "", false
+		}
+	}
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return "", false
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	var b Bytes
+	if o.SI {
+		b = NewSI(v, o.Names...)
+	} else {
+		b = New(v, o.Names...)
+	}
+	if o.Format == "" {
+		return b.short(), true
+	}
+	return fmt.Sprintf(o.Format, b), true
+}
+
+// pad pads s with spaces to the padding or, if it is zero, to the width.
+func (o *FilterOptions) pad(s string, width int) string {
+	left := false
+	if o.Padding > 0 {
+		width = o.Padding
+	} else if o.Padding < 0 {
+		width, left = -o.Padding, true
+	}
+	n := width - len([]rune(s))
+	if n <= 0 {
+		return s
+	}
+	if left {
+		return s + strings.Repeat(" ", n)
+	}
+	return strings.Repeat(" ", n) + s
+}
+
+func isBlank(c byte) bool { return c == ' ' || c == '\t' }
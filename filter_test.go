@@ -0,0 +1,101 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var filterTests = []struct {
+	name string
+	line string
+	in   string
+	opts *bytefmt.FilterOptions
+	want string
+}{
+	{
+		name: "ls",
+		line: testline(),
+		in: "-rw-r--r-- 1 root root       512 Jan  1 00:00 a\n" +
+			"-rw-r--r-- 1 root root 1610612736 Jan  1 00:00 b\n" +
+			"-rw-r--r-- 1 root root    104448 Jan  1 00:00 c\n",
+		opts: &bytefmt.FilterOptions{Fields: []int{5}},
+		want: "-rw-r--r-- 1 root root      512B Jan  1 00:00 a\n" +
+			"-rw-r--r-- 1 root root       1.5G Jan  1 00:00 b\n" +
+			"-rw-r--r-- 1 root root      102K Jan  1 00:00 c\n",
+	}, {
+		name: "all fields",
+		line: testline(),
+		in:   "  2048 x 1048576\n",
+		want: "    2K x      1M\n",
+	}, {
+		name: "wider than the field",
+		line: testline(),
+		in:   "a 1100 b\n",
+		opts: &bytefmt.FilterOptions{Format: "% .2f"},
+		want: "a 1.07 K b\n",
+	}, {
+		name: "first field without blanks",
+		line: testline(),
+		in:   "1100\tfile\n",
+		opts: &bytefmt.FilterOptions{Fields: []int{1}},
+		want: "1.1K\tfile\n",
+	}, {
+		name: "trailing blanks and no newline",
+		line: testline(),
+		in:   "file 4096  ",
+		want: "file   4K  ",
+	}, {
+		name: "csv",
+		line: testline(),
+		in:   "name,size,count\r\na,3221225472,10\r\nb,,20\r\n",
+		opts: &bytefmt.FilterOptions{Fields: []int{2}, Delimiter: ",", Header: 1},
+		want: "name,size,count\r\na,3G,10\r\nb,,20\r\n",
+	}, {
+		name: "padding",
+		line: testline(),
+		in:   "a;1536;-5\n",
+		opts: &bytefmt.FilterOptions{Delimiter: ";", Padding: 6},
+		want: "a;  1.5K;-5\n",
+	}, {
+		name: "left padding",
+		line: testline(),
+		in:   "1536 a\n",
+		opts: &bytefmt.FilterOptions{Padding: -6},
+		want: "1.5K   a\n",
+	}, {
+		name: "si",
+		line: testline(),
+		in:   "1500000 bytes\n",
+		opts: &bytefmt.FilterOptions{SI: true, Format: "%v"},
+		want: "  1.5MB bytes\n",
+	}, {
+		name: "not integers",
+		line: testline(),
+		in:   "1.5 +3 0x10 18446744073709551616\n",
+		want: "1.5 +3 0x10 18446744073709551616\n",
+	},
+}
+
+func TestFilter(t *testing.T) {
+	for _, tt := range filterTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			err := bytefmt.Filter(&b, strings.NewReader(tt.in), tt.opts)
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if b.String() != tt.want {
+				t.Errorf("\nwant output: %#v\n got output: %#v\ntest: %s", tt.want, b.String(), tt.line)
+			}
+		})
+	}
+}
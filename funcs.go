@@ -0,0 +1,152 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+/*
+	Template functions:
+		bytes VALUE		formats VALUE as %v does, e.g. 1.5G;
+		bytesf FORMAT VALUE	formats VALUE by the FORMAT, e.g. {{bytesf "% .1f" .Size}};
+		parseBytes STRING	parses a size such as 10M into the number of bytes;
+		bytesIn UNIT VALUE	converts VALUE to the UNIT, e.g. MiB or Gbit, see ParseUnit;
+		bytesRate DURATION VALUE	bytes per second transferred in the DURATION,
+				a time.Duration or a number of seconds;
+		bytesPercent TOTAL VALUE	VALUE as a percentage of the TOTAL,
+				0 when the TOTAL is zero.
+
+	A VALUE is any integer, a non-negative float, a Bytes or a size string.
+*/
+
+// FuncMap returns functions for text/template and html/template.
+// The result is assignable to both template.FuncMap types.
+func FuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"bytes":        tmplBytes,
+		"bytesf":       tmplBytesf,
+		"parseBytes":   tmplParseBytes,
+		"bytesIn":      tmplBytesIn,
+		"bytesRate":    tmplBytesRate,
+		"bytesPercent": tmplBytesPercent,
+	}
+}
+
+func tmplBytes(v interface{}) (string, error) {
+	b, err := toBytes(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", b), nil
+}
+
+func tmplBytesf(format string, v interface{}) (string, error) {
+	b, err := toBytes(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(format, b), nil
+}
+
+func tmplParseBytes(s string) (uint64, error) {
+	b, err := Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return b.Value, nil
+}
+
+func tmplBytesIn(unit string, v interface{}) (float64, error) {
+	b, err := toBytes(v)
+	if err != nil {
+		return 0, err
+	}
+	u, err := ParseUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return b.In(u), nil
+}
+
+func tmplBytesRate(d interface{}, v interface{}) (uint64, error) {
+	b, err := toBytes(v)
+	if err != nil {
+		return 0, err
+	}
+	var sec float64
+	if dur, ok := d.(time.Duration); ok {
+		sec = dur.Seconds()
+	} else {
+		sec, err = toFloat64(d)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if sec <= 0 {
+		return 0, fmt.Errorf("bytefmt: non-positive duration %v", d)
+	}
+	return uint64(math.Round(float64(b.Value) / sec)), nil
+}
+
+func tmplBytesPercent(total interface{}, v interface{}) (float64, error) {
+	t, err := toBytes(total)
+	if err != nil {
+		return 0, err
+	}
+	b, err := toBytes(v)
+	if err != nil {
+		return 0, err
+	}
+	if t.Value == 0 {
+		return 0, nil
+	}
+	return float64(b.Value) / float64(t.Value) * 100, nil
+}
+
+// toBytes converts a template argument into Bytes.
+func toBytes(v interface{}) (Bytes, error) {
+	switch v := v.(type) {
+	case Bytes:
+		return v, nil
+	case *Bytes:
+		return *v, nil
+	case string:
+		return Parse(v)
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.Int() < 0 {
+			return Bytes{}, fmt.Errorf("bytefmt: negative size %d", r.Int())
+		}
+		return New(uint64(r.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return New(r.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := r.Float()
+		if f < 0 || math.IsNaN(f) || f >= math.MaxUint64 {
+			return Bytes{}, fmt.Errorf("bytefmt: size %v out of range", f)
+		}
+		return New(uint64(math.Round(f))), nil
+	}
+	return Bytes{}, fmt.Errorf("bytefmt: cannot convert %T to a size", v)
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(r.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(r.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return r.Float(), nil
+	}
+	return 0, fmt.Errorf("bytefmt: cannot convert %T to a number", v)
+}
@@ -0,0 +1,178 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var funcMapTests = []struct {
+	name     string
+	line     string
+	template string
+	data     interface{}
+	want     string
+}{
+	{
+		name:     "bytes",
+		line:     testline(),
+		template: `{{bytes .}}`,
+		data:     int64(1024),
+		want:     "1K",
+	}, {
+		name:     "bytesf",
+		line:     testline(),
+		template: `{{bytesf "% .1f" .}}`,
+		data:     uint64(1610612736),
+		want:     "1.5 G",
+	}, {
+		name:     "bytesf pipeline",
+		line:     testline(),
+		template: `{{. | bytesf "%d"}}`,
+		data:     bytefmt.New(3 << 20),
+		want:     "3M",
+	}, {
+		name:     "size string",
+		line:     testline(),
+		template: `{{bytesf "%.1f" .}}`,
+		data:     "1536K",
+		want:     "1.5M",
+	}, {
+		name:     "parseBytes",
+		line:     testline(),
+		template: `{{parseBytes "10M"}}`,
+		want:     "10485760",
+	}, {
+		name:     "bytesIn",
+		line:     testline(),
+		template: `{{bytesIn "M" .}}`,
+		data:     1610612736,
+		want:     "1536",
+	}, {
+		name:     "bytesIn bits",
+		line:     testline(),
+		template: `{{bytesIn "Mbit" .}}`,
+		data:     "1MB",
+		want:     "8",
+	}, {
+		name:     "bytesRate",
+		line:     testline(),
+		template: `{{bytesRate .D .N | bytesf "%.1f"}}/s`,
+		data:     map[string]interface{}{"D": 2 * time.Second, "N": 3 << 20},
+		want:     "1.5M/s",
+	}, {
+		name:     "bytesRate seconds",
+		line:     testline(),
+		template: `{{bytesRate 4 .}}`,
+		data:     4096,
+		want:     "1024",
+	}, {
+		name:     "bytesPercent",
+		line:     testline(),
+		template: `{{bytesPercent "4G" "1.5G" | printf "%.1f%%"}}`,
+		want:     "37.5%",
+	}, {
+		name:     "bytesPercent zero total",
+		line:     testline(),
+		template: `{{bytesPercent 0 1}}`,
+		want:     "0",
+	},
+}
+
+func TestFuncMapText(t *testing.T) {
+	for _, tt := range funcMapTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := template.Must(template.New(tt.name).Funcs(bytefmt.FuncMap()).Parse(tt.template))
+			var got strings.Builder
+			if err := tmpl.Execute(&got, tt.data); err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if got.String() != tt.want {
+				t.Errorf("\nwant template: %#v\n got template: %#v\ntest: %s", tt.want, got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestFuncMapHTML(t *testing.T) {
+	for _, tt := range funcMapTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := htmltemplate.Must(htmltemplate.New(tt.name).Funcs(bytefmt.FuncMap()).Parse("<b>" + tt.template + "</b>"))
+			var got strings.Builder
+			if err := tmpl.Execute(&got, tt.data); err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			want := "<b>" + tt.want + "</b>"
+			if got.String() != want {
+				t.Errorf("\nwant template: %#v\n got template: %#v\ntest: %s", want, got.String(), tt.line)
+			}
+		})
+	}
+}
+
+var funcMapErrorTests = []struct {
+	name     string
+	line     string
+	template string
+	data     interface{}
+	want     string
+}{
+	{
+		name:     "negative",
+		line:     testline(),
+		template: `{{bytes .}}`,
+		data:     -1,
+		want:     "bytefmt: negative size -1",
+	}, {
+		name:     "invalid size",
+		line:     testline(),
+		template: `{{bytes .}}`,
+		data:     "1Z",
+		want:     `bytefmt: parsing "1Z": invalid syntax`,
+	}, {
+		name:     "unknown unit",
+		line:     testline(),
+		template: `{{bytesIn "Z" 1}}`,
+		want:     `bytefmt: parsing "Z": invalid syntax`,
+	}, {
+		name:     "zero duration",
+		line:     testline(),
+		template: `{{bytesRate 0 1}}`,
+		want:     "bytefmt: non-positive duration 0",
+	}, {
+		name:     "unsupported type",
+		line:     testline(),
+		template: `{{bytes .}}`,
+		data:     true,
+		want:     "bytefmt: cannot convert bool to a size",
+	},
+}
+
+func TestFuncMapError(t *testing.T) {
+	for _, tt := range funcMapErrorTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := template.Must(template.New(tt.name).Funcs(bytefmt.FuncMap()).Parse(tt.template))
+			err := tmpl.Execute(&strings.Builder{}, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("\nwant error: %#v\n got error: %v\ntest: %s", tt.want, err, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+
+package bytefmt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNegative is returned by Of for negative sizes.
+var ErrNegative = errors.New("bytefmt: negative size")
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Of returns the size v as Bytes, or ErrNegative if v is negative.
+func Of[T Integer](v T, n ...string) (Bytes, error) {
+	m, neg := magnitude(v)
+	if neg {
+		return Bytes{}, ErrNegative
+	}
+	return New(m, n...), nil
+}
+
+// Size is a size of any integer type, negative sizes are printed with a sign.
+type Size[T Integer] struct {
+	Value T
+	names []string
+}
+
+func NewSize[T Integer](v T, n ...string) Size[T] {
+	s := Size[T]{Value: v}
+	s.Names(n...)
+	return s
+}
+
+func (s *Size[T]) Names(n ...string) []string {
+	b := Bytes{names: s.names}
+	s.names = b.Names(n...)
+	return s.names
+}
+
+// Bytes returns the absolute value of the size.
+func (s Size[T]) Bytes() Bytes {
+	m, _ := magnitude(s.Value)
+	return Bytes{Value: m, names: s.names}
+}
+
+func (s Size[T]) String() string {
+	return fmt.Sprintf("%v", s)
+}
+
+func (s Size[T]) Format(f fmt.State, c rune) {
+	m, neg := magnitude(s.Value)
+	Bytes{Value: m, names: s.names}.format(f, c, neg)
+}
+
+// magnitude returns the absolute value of v and whether v is negative.
+func magnitude[T Integer](v T) (uint64, bool) {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1, true
+	}
+	return uint64(v), false
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+
+package bytefmt_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var sizeFormatTests = []struct {
+	name   string
+	line   string
+	size   fmt.Formatter
+	format string
+	want   string
+	bench  bool
+}{
+	{
+		name:   "int",
+		line:   testline(),
+		size:   bytefmt.NewSize(1024),
+		format: "%v",
+		want:   "1K",
+	}, {
+		name:   "negative int",
+		line:   testline(),
+		size:   bytefmt.NewSize(-1536),
+		format: "%v",
+		want:   "-1.5K",
+		bench:  true,
+	}, {
+		name:   "negative int64 with space",
+		line:   testline(),
+		size:   bytefmt.NewSize(int64(-3 << 30)),
+		format: "% d",
+		want:   "-3 G",
+	}, {
+		name:   "negative int with width",
+		line:   testline(),
+		size:   bytefmt.NewSize(-1536),
+		format: "%8.1f",
+		want:   "   -1.5K",
+	}, {
+		name:   "negative int with zero padding",
+		line:   testline(),
+		size:   bytefmt.NewSize(-1536),
+		format: "%08.1f",
+		want:   "-0001.5K",
+	}, {
+		name:   "negative string",
+		line:   testline(),
+		size:   bytefmt.NewSize(-2048),
+		format: "%q",
+		want:   `"-2K"`,
+	}, {
+		name:   "positive with plus",
+		line:   testline(),
+		size:   bytefmt.NewSize(int32(2048)),
+		format: "%+d",
+		want:   "+2K",
+	}, {
+		name:   "uint32",
+		line:   testline(),
+		size:   bytefmt.NewSize(uint32(1 << 20)),
+		format: "%v",
+		want:   "1M",
+	}, {
+		name:   "uintptr",
+		line:   testline(),
+		size:   bytefmt.NewSize(uintptr(512)),
+		format: "%v",
+		want:   "512B",
+	}, {
+		name:   "smallest int8",
+		line:   testline(),
+		size:   bytefmt.NewSize(int8(math.MinInt8)),
+		format: "%v",
+		want:   "-128B",
+	}, {
+		name:   "smallest int64",
+		line:   testline(),
+		size:   bytefmt.NewSize(int64(math.MinInt64)),
+		format: "%v",
+		want:   "-8E",
+	}, {
+		name:   "names",
+		line:   testline(),
+		size:   bytefmt.NewSize(-1024, "B", "Kilobyte"),
+		format: "% v",
+		want:   "-1 Kilobyte",
+	},
+}
+
+func TestSizeFormat(t *testing.T) {
+	for _, tt := range sizeFormatTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			got := fmt.Sprintf(tt.format, tt.size)
+			if got != tt.want {
+				t.Errorf("\nwant size: %#v\n got size: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func BenchmarkSizeFormat(b *testing.B) {
+	b.ReportAllocs()
+
+	for _, tt := range sizeFormatTests {
+		if !tt.bench {
+			continue
+		}
+
+		b.Run(tt.line+"/"+tt.name+" "+tt.format, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = fmt.Sprintf(tt.format, tt.size)
+			}
+		})
+	}
+}
+
+func TestSizeString(t *testing.T) {
+	got := bytefmt.NewSize(int64(-1536)).String()
+	if got != "-1.5K" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "-1.5K", got)
+	}
+}
+
+func TestSizeBytes(t *testing.T) {
+	got := bytefmt.NewSize(-1536).Bytes()
+	if got.Value != 1536 {
+		t.Errorf("\nwant bytes: %d\n got bytes: %d", 1536, got.Value)
+	}
+}
+
+func TestOf(t *testing.T) {
+	b, err := bytefmt.Of(int64(4096))
+	if err != nil || b.Value != 4096 {
+		t.Errorf("\nwant bytes: 4096 <nil>\n got bytes: %d %v", b.Value, err)
+	}
+	b, err = bytefmt.Of(uintptr(4096))
+	if err != nil || b.Value != 4096 {
+		t.Errorf("\nwant bytes: 4096 <nil>\n got bytes: %d %v", b.Value, err)
+	}
+	_, err = bytefmt.Of(-1)
+	if !errors.Is(err, bytefmt.ErrNegative) {
+		t.Errorf("\nwant error: %v\n got error: %v", bytefmt.ErrNegative, err)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/pfmt/bytefmt
 
-go 1.17
+go 1.18
@@ -0,0 +1,123 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httplog provides a net/http middleware logging request
+// and response sizes.
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// Stats describes a served request.
+type Stats struct {
+	Request  bytefmt.Bytes // request body bytes read by the handler
+	Response bytefmt.Bytes // response body bytes written by the handler
+	Status   int
+	Duration time.Duration
+}
+
+// LogFunc is called after the handler returns.
+type LogFunc func(r *http.Request, s Stats)
+
+// Handler returns a handler counting the body bytes read and written by h,
+// regardless of Content-Length, and passing them to log.
+func Handler(h http.Handler, log LogFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		var body *bytefmt.CountingReader
+		if r.Body != nil {
+			body = bytefmt.NewCountingReader(r.Body)
+			r2 := *r
+			r2.Body = &readCloser{CountingReader: body, c: r.Body}
+			r = &r2
+		}
+		rw := &responseWriter{CountingWriter: bytefmt.NewCountingWriter(w), w: w}
+
+		h.ServeHTTP(rw, r)
+
+		s := Stats{Response: rw.Bytes(), Status: rw.status, Duration: time.Since(start)}
+		if body != nil {
+			s.Request = body.Bytes()
+		}
+		if s.Status == 0 {
+			s.Status = http.StatusOK
+		}
+		log(r, s)
+	})
+}
+
+// Middleware returns a function wrapping handlers with Handler.
+func Middleware(log LogFunc) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return Handler(h, log)
+	}
+}
+
+type readCloser struct {
+	*bytefmt.CountingReader
+	c io.Closer
+}
+
+func (r *readCloser) Close() error {
+	return r.c.Close()
+}
+
+type responseWriter struct {
+	*bytefmt.CountingWriter
+	w      http.ResponseWriter
+	status int
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.w.Header()
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 && status >= 200 {
+		w.status = status
+	}
+	w.w.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.CountingWriter.Write(p)
+}
+
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.CountingWriter.ReadFrom(r)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, e.g. for WebSocket
+// upgrades, if the underlying response writer supports it.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying response writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.w
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httplog_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt/httplog"
+)
+
+var handlerTests = []struct {
+	name     string
+	line     string
+	body     string
+	length   int64
+	handler  http.HandlerFunc
+	request  uint64
+	response uint64
+	status   int
+}{
+	{
+		name: "no body",
+		line: testline(),
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "hello")
+		},
+		response: 5,
+		status:   http.StatusOK,
+	}, {
+		name:   "content length",
+		line:   testline(),
+		body:   strings.Repeat("x", 2048),
+		length: 2048,
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			n, _ := io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, n)
+		},
+		request:  2048,
+		response: 4,
+		status:   http.StatusCreated,
+	}, {
+		name:   "chunked",
+		line:   testline(),
+		body:   strings.Repeat("x", 3<<20),
+		length: -1,
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(w, r.Body)
+			w.(http.Flusher).Flush()
+			_, _ = io.WriteString(w, "!")
+		},
+		request:  3 << 20,
+		response: 3<<20 + 1,
+		status:   http.StatusOK,
+	}, {
+		name:   "partially read body",
+		line:   testline(),
+		body:   strings.Repeat("x", 1024),
+		length: 1024,
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = r.Body.Read(make([]byte, 100))
+			http.Error(w, "bad", http.StatusBadRequest)
+		},
+		request:  100,
+		response: 4,
+		status:   http.StatusBadRequest,
+	},
+}
+
+func TestHandler(t *testing.T) {
+	for _, tt := range handlerTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got httplog.Stats
+			h := httplog.Middleware(func(r *http.Request, s httplog.Stats) {
+				got = s
+			})(tt.handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.ContentLength = tt.length
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got.Request.Value != tt.request || got.Response.Value != tt.response || got.Status != tt.status {
+				t.Errorf("\nwant stats: %d %d %d\n got stats: %d %d %d\ntest: %s",
+					tt.request, tt.response, tt.status, got.Request.Value, got.Response.Value, got.Status, tt.line)
+			}
+			if uint64(rec.Body.Len()) != tt.response {
+				t.Errorf("\nwant recorded: %d\n got recorded: %d\ntest: %s", tt.response, rec.Body.Len(), tt.line)
+			}
+		})
+	}
+}
+
+func TestHandlerServer(t *testing.T) {
+	logged := make(chan string, 1)
+	srv := httptest.NewServer(httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	}), func(r *http.Request, s httplog.Stats) {
+		logged <- fmt.Sprintf("%s %s %d in=%.1f out=%.1f", r.Method, r.URL.Path, s.Status, s.Request, s.Response)
+	}))
+	defer srv.Close()
+
+	body := io.MultiReader(strings.NewReader(strings.Repeat("x", 1<<20)), strings.NewReader(strings.Repeat("x", 1<<19)))
+	resp, err := http.Post(srv.URL+"/upload", "text/plain", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	want := "POST /upload 200 in=1.5M out=1.5M"
+	if got := <-logged; got != want {
+		t.Errorf("\nwant log: %#v\n got log: %#v", want, got)
+	}
+}
+
+func TestHandlerHijack(t *testing.T) {
+	srv := httptest.NewServer(httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+		_ = rw.Flush()
+	}), func(r *http.Request, s httplog.Stats) {}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("\nwant status: %d\n got status: %d", http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestHandlerHijackNotSupported(t *testing.T) {
+	h := httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err != http.ErrNotSupported {
+			t.Errorf("\nwant error: %v\n got error: %v", http.ErrNotSupported, err)
+		}
+	}), func(r *http.Request, s httplog.Stats) {})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func testline() string {
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "it was not possible to recover file and line number information about function invocations"
+}
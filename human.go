@@ -0,0 +1,63 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// Human formats v as GNU coreutils do with -h (--human-readable):
+// powers of 1024, rounding up and a single decimal below 10,
+// e.g. 512, 4.0K, 1.5M, 12G.
+func Human(v uint64) string {
+	return human(v, 1024)
+}
+
+// HumanSI formats v as GNU coreutils do with -H (--si):
+// powers of 1000, rounding up and a single decimal below 10,
+// e.g. 512, 4.1k, 1.6M, 13G.
+func HumanSI(v uint64) string {
+	return human(v, 1000)
+}
+
+func human(v, base uint64) string {
+	if v < base {
+		return strconv.FormatUint(v, 10)
+	}
+	p, d := 1, base
+	for p < 6 && v/d >= base {
+		p++
+		d *= base
+	}
+	for {
+		// tenths rounded up
+		hi, lo := bits.Mul64(v, 10)
+		t, r := bits.Div64(hi, lo, d)
+		if r != 0 {
+			t++
+		}
+		if t < 100 {
+			return strconv.FormatUint(t/10, 10) + "." + strconv.FormatUint(t%10, 10) + humanLetter(p, base)
+		}
+		n := v / d
+		if v%d != 0 {
+			n++
+		}
+		if n >= base && p < 6 {
+			p++
+			d *= base
+			continue
+		}
+		return strconv.FormatUint(n, 10) + humanLetter(p, base)
+	}
+}
+
+func humanLetter(p int, base uint64) string {
+	if p == 1 && base == 1000 {
+		return "k"
+	}
+	return string("KMGTPE"[p-1])
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var humanTests = []struct {
+	name  string
+	line  string
+	bytes uint64
+	want  string
+	si    string
+	bench bool
+}{
+	{
+		name:  "zero",
+		line:  testline(),
+		bytes: 0,
+		want:  "0",
+		si:    "0",
+	}, {
+		name:  "bytes",
+		line:  testline(),
+		bytes: 999,
+		want:  "999",
+		si:    "999",
+	}, {
+		name:  "less than a binary kilobyte",
+		line:  testline(),
+		bytes: 1023,
+		want:  "1023",
+		si:    "1.1k",
+	}, {
+		name:  "exactly one kilobyte",
+		line:  testline(),
+		bytes: 1024,
+		want:  "1.0K",
+		si:    "1.1k",
+	}, {
+		name:  "rounded up",
+		line:  testline(),
+		bytes: 1025,
+		want:  "1.1K",
+		si:    "1.1k",
+	}, {
+		name:  "page",
+		line:  testline(),
+		bytes: 4096,
+		want:  "4.0K",
+		si:    "4.1k",
+		bench: true,
+	}, {
+		name:  "ten",
+		line:  testline(),
+		bytes: 10240,
+		want:  "10K",
+		si:    "11k",
+	}, {
+		name:  "tenths rounded up to ten",
+		line:  testline(),
+		bytes: 10200,
+		want:  "10K",
+		si:    "11k",
+	}, {
+		name:  "integer rounded up",
+		line:  testline(),
+		bytes: 12289,
+		want:  "13K",
+		si:    "13k",
+	}, {
+		name:  "rounded up to the next unit",
+		line:  testline(),
+		bytes: 1<<20 - 1,
+		want:  "1.0M",
+		si:    "1.1M",
+	}, {
+		name:  "megabytes",
+		line:  testline(),
+		bytes: 1610612736,
+		want:  "1.5G",
+		si:    "1.7G",
+	}, {
+		name:  "largest",
+		line:  testline(),
+		bytes: math.MaxUint64,
+		want:  "16E",
+		si:    "19E",
+	},
+}
+
+func TestHuman(t *testing.T) {
+	for _, tt := range humanTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+strconv.FormatUint(tt.bytes, 10), func(t *testing.T) {
+			t.Parallel()
+
+			if got := bytefmt.Human(tt.bytes); got != tt.want {
+				t.Errorf("\nwant human: %#v\n got human: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+			if got := bytefmt.HumanSI(tt.bytes); got != tt.si {
+				t.Errorf("\nwant human SI: %#v\n got human SI: %#v\ntest: %s", tt.si, got, tt.line)
+			}
+		})
+	}
+}
+
+func BenchmarkHuman(b *testing.B) {
+	b.ReportAllocs()
+
+	for _, tt := range humanTests {
+		if !tt.bench {
+			continue
+		}
+
+		b.Run(tt.line+"/"+tt.name+" "+strconv.FormatUint(tt.bytes, 10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = bytefmt.Human(tt.bytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+	Layout placeholders:
+		{value}		number in the selected unit of measure (%v);
+		{value:spec}	number formatted by the printf-style spec without
+				the leading percent sign, for example {value:.2}
+				or {value:08.3f}; the verb defaults to f when
+				a precision is given and to v otherwise;
+		{unit}		name of the selected unit of measure;
+		{sep}		a space separating the value from the unit;
+		{bytes}		value in bytes.
+
+	Braces are escaped by doubling them: {{ and }}.
+*/
+
+// Layout is a compiled layout string, safe for concurrent use.
+type Layout struct {
+	layout string
+	parts  []layoutPart
+}
+
+type layoutPart struct {
+	kind layoutKind
+	text string // literal text or value format
+}
+
+type layoutKind int
+
+const (
+	layoutText layoutKind = iota
+	layoutValue
+	layoutUnit
+	layoutSep
+	layoutBytes
+)
+
+// LayoutError describes a problem compiling a layout string.
+type LayoutError struct {
+	Layout string
+	Offset int
+	Msg    string
+}
+
+func (e *LayoutError) Error() string {
+	return "bytefmt: layout " + strconv.Quote(e.Layout) + ": " + e.Msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+// NewLayout compiles a layout string.
+func NewLayout(s string) (*Layout, error) {
+	l := &Layout{layout: s}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() != 0 {
+			l.parts = append(l.parts, layoutPart{kind: layoutText, text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			if i+1 < len(s) && s[i+1] == '{' {
+				text.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return nil, &LayoutError{Layout: s, Offset: i, Msg: "unclosed placeholder"}
+			}
+			p, err := parseLayoutPart(s[i+1 : i+end])
+			if err != "" {
+				return nil, &LayoutError{Layout: s, Offset: i, Msg: err}
+			}
+			flush()
+			l.parts = append(l.parts, p)
+			i += end
+		case '}':
+			if i+1 < len(s) && s[i+1] == '}' {
+				text.WriteByte('}')
+				i++
+				continue
+			}
+			return nil, &LayoutError{Layout: s, Offset: i, Msg: "unexpected }"}
+		default:
+			text.WriteByte(s[i])
+		}
+	}
+	flush()
+	return l, nil
+}
+
+// MustLayout is like NewLayout but panics if the layout cannot be compiled.
+func MustLayout(s string) *Layout {
+	l, err := NewLayout(s)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func parseLayoutPart(p string) (layoutPart, string) {
+	name, spec := p, ""
+	hasSpec := false
+	if i := strings.IndexByte(p, ':'); i != -1 {
+		name, spec, hasSpec = p[:i], p[i+1:], true
+	}
+	switch name {
+	case "value":
+		if !hasSpec {
+			return layoutPart{kind: layoutValue, text: "%v"}, ""
+		}
+		f, ok := valueFormat(spec)
+		if !ok {
+			return layoutPart{}, "invalid value format " + strconv.Quote(spec)
+		}
+		return layoutPart{kind: layoutValue, text: f}, ""
+	case "unit":
+		if hasSpec {
+			return layoutPart{}, "placeholder {unit} takes no format"
+		}
+		return layoutPart{kind: layoutUnit}, ""
+	case "sep":
+		if hasSpec {
+			return layoutPart{}, "placeholder {sep} takes no format"
+		}
+		return layoutPart{kind: layoutSep}, ""
+	case "bytes":
+		if hasSpec {
+			return layoutPart{}, "placeholder {bytes} takes no format"
+		}
+		return layoutPart{kind: layoutBytes}, ""
+	}
+	return layoutPart{}, "unknown placeholder {" + p + "}"
+}
+
+// valueFormat converts a printf-style spec without the leading percent sign
+// into a format for the value placeholder.
+func valueFormat(spec string) (string, bool) {
+	i := 0
+	for i < len(spec) && strings.IndexByte("+-# 0", spec[i]) != -1 {
+		i++
+	}
+	for i < len(spec) && spec[i] >= '0' && spec[i] <= '9' {
+		i++
+	}
+	prec := false
+	if i < len(spec) && spec[i] == '.' {
+		prec = true
+		i++
+		for i < len(spec) && spec[i] >= '0' && spec[i] <= '9' {
+			i++
+		}
+	}
+	switch spec[i:] {
+	case "":
+		if prec {
+			return "%" + spec + "f", true
+		}
+		return "%" + spec + "v", true
+	case "v", "f", "F", "e", "E", "g", "G", "d":
+		return "%" + spec, true
+	}
+	return "", false
+}
+
+// Format returns b formatted according to the layout.
+func (l *Layout) Format(b Bytes) string {
+	var s strings.Builder
+	for _, p := range l.parts {
+		switch p.kind {
+		case layoutText:
+			s.WriteString(p.text)
+		case layoutValue:
+			if p.text[len(p.text)-1] == 'd' {
+				fmt.Fprintf(&s, p.text, int64(math.Round(b.float64())))
+			} else {
+				fmt.Fprintf(&s, p.text, b.float64())
+			}
+		case layoutUnit:
+			s.WriteString(b.name())
+		case layoutSep:
+			s.WriteByte(' ')
+		case layoutBytes:
+			s.WriteString(strconv.FormatUint(b.Value, 10))
+		}
+	}
+	return s.String()
+}
+
+// String returns the source of the layout.
+func (l *Layout) String() string {
+	return l.layout
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var layoutTests = []struct {
+	name   string
+	line   string
+	layout string
+	bytes  uint64
+	names  []string
+	want   string
+	bench  bool
+}{
+	{
+		name:   "value and unit",
+		line:   testline(),
+		layout: "{value}{unit}",
+		bytes:  1128,
+		want:   "1.1015625K",
+	}, {
+		name:   "precision and separator",
+		line:   testline(),
+		layout: "{value:.2}{sep}{unit}/s",
+		bytes:  1128,
+		want:   "1.10 K/s",
+		bench:  true,
+	}, {
+		name:   "unit before value",
+		line:   testline(),
+		layout: "{unit}{sep}{value:.1f}",
+		bytes:  1610612736,
+		want:   "G 1.5",
+	}, {
+		name:   "integer value",
+		line:   testline(),
+		layout: "{value:d}{unit}",
+		bytes:  1610612736,
+		want:   "2G",
+	}, {
+		name:   "width and flags",
+		line:   testline(),
+		layout: "[{value:-6.1}|{value:06.1}]",
+		bytes:  1536,
+		want:   "[1.5   |0001.5]",
+	}, {
+		name:   "bytes",
+		line:   testline(),
+		layout: "{value}{unit} ({bytes} bytes)",
+		bytes:  2048,
+		want:   "2K (2048 bytes)",
+	}, {
+		name:   "escaped braces",
+		line:   testline(),
+		layout: "{{{value}}}",
+		bytes:  1,
+		want:   "{1}",
+	}, {
+		name:   "names",
+		line:   testline(),
+		layout: "{value}{sep}{unit}",
+		bytes:  1024,
+		names:  []string{"B", "Kilobyte"},
+		want:   "1 Kilobyte",
+	},
+}
+
+func TestLayoutFormat(t *testing.T) {
+	for _, tt := range layoutTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.layout+" "+strconv.FormatUint(tt.bytes, 10), func(t *testing.T) {
+			t.Parallel()
+
+			l, err := bytefmt.NewLayout(tt.layout)
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			got := l.Format(bytefmt.New(tt.bytes, tt.names...))
+			if got != tt.want {
+				t.Errorf("\nwant layout: %#v\n got layout: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func BenchmarkLayoutFormat(b *testing.B) {
+	b.ReportAllocs()
+
+	for _, tt := range layoutTests {
+		if !tt.bench {
+			continue
+		}
+
+		l := bytefmt.MustLayout(tt.layout)
+		b.Run(tt.line+"/"+tt.name+" "+tt.layout+" "+strconv.FormatUint(tt.bytes, 10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = l.Format(bytefmt.New(tt.bytes, tt.names...))
+			}
+		})
+	}
+}
+
+var layoutErrorTests = []struct {
+	name   string
+	line   string
+	layout string
+	want   string
+}{
+	{
+		name:   "unknown placeholder",
+		line:   testline(),
+		layout: "{value}{units}",
+		want:   `bytefmt: layout "{value}{units}": unknown placeholder {units} at offset 7`,
+	}, {
+		name:   "unclosed placeholder",
+		line:   testline(),
+		layout: "{value",
+		want:   `bytefmt: layout "{value": unclosed placeholder at offset 0`,
+	}, {
+		name:   "unexpected brace",
+		line:   testline(),
+		layout: "{value}}",
+		want:   `bytefmt: layout "{value}}": unexpected } at offset 7`,
+	}, {
+		name:   "invalid value format",
+		line:   testline(),
+		layout: "{value:.2x}",
+		want:   `bytefmt: layout "{value:.2x}": invalid value format ".2x" at offset 0`,
+	}, {
+		name:   "unit format",
+		line:   testline(),
+		layout: "{unit:5}",
+		want:   `bytefmt: layout "{unit:5}": placeholder {unit} takes no format at offset 0`,
+	},
+}
+
+func TestLayoutError(t *testing.T) {
+	for _, tt := range layoutErrorTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.layout, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := bytefmt.NewLayout(tt.layout)
+			if err == nil {
+				t.Fatalf("\nwant error: %#v\n got error: nil\ntest: %s", tt.want, tt.line)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("\nwant error: %#v\n got error: %#v\ntest: %s", tt.want, err.Error(), tt.line)
+			}
+		})
+	}
+}
+
+func TestMustLayoutPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic")
+		}
+	}()
+	bytefmt.MustLayout("{nope}")
+}
+
+func TestLayoutZeroBytes(t *testing.T) {
+	got := bytefmt.MustLayout("{value}{unit}").Format(bytefmt.Bytes{Value: 2048})
+	if got != "2K" {
+		t.Errorf("\nwant layout: %#v\n got layout: %#v", "2K", got)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+)
+
+// LimitError is returned once more than Limit bytes are read.
+type LimitError struct {
+	What  string // what is read, e.g. "body"
+	Limit Bytes
+}
+
+func (e *LimitError) Error() string {
+	return "bytefmt: " + e.What + " exceeds " + e.Limit.short() + " limit"
+}
+
+// LimitedReader reads from the underlying reader up to the limit
+// and returns a *LimitError if the underlying reader has more data.
+type LimitedReader struct {
+	r     io.Reader
+	limit Bytes
+	n     uint64 // bytes remaining
+	what  string
+	err   error
+}
+
+// LimitReader returns a reader of at most limit bytes.
+func LimitReader(r io.Reader, limit Bytes) *LimitedReader {
+	return &LimitedReader{r: r, limit: limit, n: limit.Value, what: "input"}
+}
+
+// LimitReaderString is like LimitReader but takes a size such as "10M",
+// see Parse.
+func LimitReaderString(r io.Reader, limit string) (*LimitedReader, error) {
+	b, err := Parse(limit)
+	if err != nil {
+		return nil, err
+	}
+	return LimitReader(r, b), nil
+}
+
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if l.n == 0 {
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			l.err = &LimitError{What: l.what, Limit: l.limit}
+			return 0, l.err
+		}
+		return 0, err
+	}
+	if uint64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= uint64(n)
+	return n, err
+}
+
+// MaxBytesReader is like http.MaxBytesReader but returns a *LimitError
+// mentioning the limit, e.g. "bytefmt: body exceeds 10M limit".
+// As http.MaxBytesReader does, it tells the server to close the connection
+// once the limit is exceeded.
+func MaxBytesReader(w http.ResponseWriter, r io.ReadCloser, limit Bytes) io.ReadCloser {
+	n := int64(math.MaxInt64)
+	if limit.Value < math.MaxInt64 {
+		n = int64(limit.Value)
+	}
+	rc := http.MaxBytesReader(w, r, n)
+	return &maxBytesReader{
+		LimitedReader: LimitedReader{r: rc, limit: limit, n: limit.Value, what: "body"},
+		c:             rc,
+	}
+}
+
+type maxBytesReader struct {
+	LimitedReader
+	c io.Closer
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	n, err := m.LimitedReader.Read(p)
+	var e *LimitError
+	if err != nil && err != io.EOF && m.n == 0 && !errors.As(err, &e) {
+		// The error of http.MaxBytesReader once the limit is exceeded.
+		m.err = &LimitError{What: m.what, Limit: m.limit}
+		return n, m.err
+	}
+	return n, err
+}
+
+func (m *maxBytesReader) Close() error {
+	return m.c.Close()
+}
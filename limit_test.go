@@ -0,0 +1,146 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var limitTests = []struct {
+	name  string
+	line  string
+	limit string
+	input string
+	want  string
+	err   string
+}{
+	{
+		name:  "under the limit",
+		line:  testline(),
+		limit: "1K",
+		input: strings.Repeat("x", 1000),
+		want:  strings.Repeat("x", 1000),
+	}, {
+		name:  "at the limit",
+		line:  testline(),
+		limit: "1K",
+		input: strings.Repeat("x", 1024),
+		want:  strings.Repeat("x", 1024),
+	}, {
+		name:  "over the limit",
+		line:  testline(),
+		limit: "1K",
+		input: strings.Repeat("x", 1025),
+		want:  strings.Repeat("x", 1024),
+		err:   "bytefmt: input exceeds 1K limit",
+	}, {
+		name:  "fractional limit",
+		line:  testline(),
+		limit: "1.5K",
+		input: strings.Repeat("x", 2048),
+		want:  strings.Repeat("x", 1536),
+		err:   "bytefmt: input exceeds 1.5K limit",
+	}, {
+		name:  "zero limit",
+		line:  testline(),
+		limit: "0",
+		input: "x",
+		err:   "bytefmt: input exceeds 0B limit",
+	}, {
+		name:  "empty input with zero limit",
+		line:  testline(),
+		limit: "0",
+	},
+}
+
+func TestLimitReader(t *testing.T) {
+	for _, tt := range limitTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := bytefmt.LimitReaderString(strings.NewReader(tt.input), tt.limit)
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			got, err := io.ReadAll(r)
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("\nwant error: %#v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if string(got) != tt.want {
+				t.Errorf("\nwant length: %d\n got length: %d\ntest: %s", len(tt.want), len(got), tt.line)
+			}
+		})
+	}
+}
+
+func TestLimitReaderError(t *testing.T) {
+	r := bytefmt.LimitReader(strings.NewReader("xx"), bytefmt.New(1))
+	_, err := io.ReadAll(r)
+	var e *bytefmt.LimitError
+	if !errors.As(err, &e) || e.Limit.Value != 1 {
+		t.Fatalf("\nwant limit error: 1\n got error: %v", err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != e {
+		t.Errorf("\nwant repeated error: %v\n got error: %v", e, err)
+	}
+}
+
+func TestLimitReaderStringSyntax(t *testing.T) {
+	_, err := bytefmt.LimitReaderString(strings.NewReader(""), "10Z")
+	if !errors.Is(err, bytefmt.ErrSyntax) {
+		t.Errorf("\nwant error: %v\n got error: %v", bytefmt.ErrSyntax, err)
+	}
+}
+
+func TestMaxBytesReader(t *testing.T) {
+	limit, _ := bytefmt.Parse("10")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = bytefmt.MaxBytesReader(w, r.Body, limit)
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	})
+
+	for _, body := range []string{"0123456789", "0123456789x"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		want := ""
+		if len(body) > 10 {
+			want = "bytefmt: body exceeds 10B limit\n"
+		}
+		if rec.Body.String() != want {
+			t.Errorf("\nwant response: %#v\n got response: %#v", want, rec.Body.String())
+		}
+	}
+}
+
+func TestMaxBytesReaderServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = bytefmt.MaxBytesReader(w, r.Body, bytefmt.New(1024))
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "text/plain", strings.NewReader(strings.Repeat("x", 4096)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || string(got) != "bytefmt: body exceeds 1K limit\n" {
+		t.Errorf("\nwant response: %d %#v\n got response: %d %#v",
+			http.StatusRequestEntityTooLarge, "bytefmt: body exceeds 1K limit\n", resp.StatusCode, string(got))
+	}
+}
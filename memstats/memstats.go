@@ -0,0 +1,177 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package memstats formats Go runtime memory statistics.
+package memstats
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"strings"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// Field is a named memory statistic.
+type Field struct {
+	Name  string
+	Value bytefmt.Bytes
+}
+
+// Summary is a snapshot of memory statistics.
+type Summary []Field
+
+// Read returns the byte fields of runtime.ReadMemStats.
+func Read() Summary {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return FromMemStats(&m)
+}
+
+// FromMemStats returns the byte fields of m.
+func FromMemStats(m *runtime.MemStats) Summary {
+	return Summary{
+		{"Alloc", bytefmt.New(m.Alloc)},
+		{"TotalAlloc", bytefmt.New(m.TotalAlloc)},
+		{"Sys", bytefmt.New(m.Sys)},
+		{"HeapAlloc", bytefmt.New(m.HeapAlloc)},
+		{"HeapSys", bytefmt.New(m.HeapSys)},
+		{"HeapIdle", bytefmt.New(m.HeapIdle)},
+		{"HeapInuse", bytefmt.New(m.HeapInuse)},
+		{"HeapReleased", bytefmt.New(m.HeapReleased)},
+		{"StackInuse", bytefmt.New(m.StackInuse)},
+		{"StackSys", bytefmt.New(m.StackSys)},
+		{"MSpanInuse", bytefmt.New(m.MSpanInuse)},
+		{"MSpanSys", bytefmt.New(m.MSpanSys)},
+		{"MCacheInuse", bytefmt.New(m.MCacheInuse)},
+		{"MCacheSys", bytefmt.New(m.MCacheSys)},
+		{"BuckHashSys", bytefmt.New(m.BuckHashSys)},
+		{"GCSys", bytefmt.New(m.GCSys)},
+		{"OtherSys", bytefmt.New(m.OtherSys)},
+		{"NextGC", bytefmt.New(m.NextGC)},
+	}
+}
+
+// ReadMetrics returns every runtime/metrics metric measured in bytes.
+func ReadMetrics() Summary {
+	var samples []metrics.Sample
+	for _, d := range metrics.All() {
+		if d.Kind == metrics.KindUint64 && strings.HasSuffix(d.Name, ":bytes") {
+			samples = append(samples, metrics.Sample{Name: d.Name})
+		}
+	}
+	metrics.Read(samples)
+	return FromMetrics(samples)
+}
+
+// FromMetrics returns the samples measured in bytes,
+// other samples are skipped.
+func FromMetrics(samples []metrics.Sample) Summary {
+	var s Summary
+	for _, m := range samples {
+		if m.Value.Kind() != metrics.KindUint64 || !strings.HasSuffix(m.Name, ":bytes") {
+			continue
+		}
+		s = append(s, Field{Name: m.Name, Value: bytefmt.New(m.Value.Uint64())})
+	}
+	return s
+}
+
+// Get returns the value of the named field.
+func (s Summary) Get(name string) (bytefmt.Bytes, bool) {
+	for _, f := range s {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return bytefmt.Bytes{}, false
+}
+
+// String returns a line per field with aligned values, e.g.
+//
+//	HeapAlloc  1.5M
+//	HeapSys    7.5M
+func (s Summary) String() string {
+	w := 0
+	for _, f := range s {
+		if len(f.Name) > w {
+			w = len(f.Name)
+		}
+	}
+	var b strings.Builder
+	for _, f := range s {
+		b.WriteString(f.Name + strings.Repeat(" ", w-len(f.Name)+2) + bytefmt.Human(f.Value.Value) + "\n")
+	}
+	return b.String()
+}
+
+// Line returns the fields on a single line, e.g. "HeapAlloc=1.5M HeapSys=7.5M".
+func (s Summary) Line() string {
+	parts := make([]string, len(s))
+	for i, f := range s {
+		parts[i] = f.Name + "=" + bytefmt.Human(f.Value.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Change is the change of a field between two snapshots.
+type Change struct {
+	Name   string
+	Before bytefmt.Bytes
+	After  bytefmt.Bytes
+}
+
+// Delta returns the signed difference, e.g. +512K or -1.0M.
+func (c Change) Delta() string {
+	if c.After.Value >= c.Before.Value {
+		return "+" + bytefmt.Human(c.After.Value-c.Before.Value)
+	}
+	return "-" + bytefmt.Human(c.Before.Value-c.After.Value)
+}
+
+// Changes is the difference between two snapshots.
+type Changes []Change
+
+// Diff returns the changes of the fields present in both snapshots.
+func Diff(before, after Summary) Changes {
+	var c Changes
+	for _, f := range before {
+		if v, ok := after.Get(f.Name); ok {
+			c = append(c, Change{Name: f.Name, Before: f.Value, After: v})
+		}
+	}
+	return c
+}
+
+// String returns a line per changed field, e.g.
+//
+//	HeapAlloc  1.5M -> 2.0M (+512K)
+func (c Changes) String() string {
+	w := 0
+	for _, ch := range c {
+		if ch.Before.Value != ch.After.Value && len(ch.Name) > w {
+			w = len(ch.Name)
+		}
+	}
+	var b strings.Builder
+	for _, ch := range c {
+		if ch.Before.Value == ch.After.Value {
+			continue
+		}
+		b.WriteString(ch.Name + strings.Repeat(" ", w-len(ch.Name)+2) +
+			bytefmt.Human(ch.Before.Value) + " -> " + bytefmt.Human(ch.After.Value) + " (" + ch.Delta() + ")\n")
+	}
+	return b.String()
+}
+
+// Line returns the changed fields on a single line, e.g. "HeapAlloc=+512K".
+func (c Changes) Line() string {
+	var parts []string
+	for _, ch := range c {
+		if ch.Before.Value != ch.After.Value {
+			parts = append(parts, ch.Name+"="+ch.Delta())
+		}
+	}
+	return strings.Join(parts, " ")
+}
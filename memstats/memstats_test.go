@@ -0,0 +1,105 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memstats_test
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+	"github.com/pfmt/bytefmt/memstats"
+)
+
+var before = &runtime.MemStats{
+	HeapAlloc:  1536 << 10,
+	HeapSys:    7680 << 10,
+	StackInuse: 512 << 10,
+	NextGC:     4 << 20,
+}
+
+var after = &runtime.MemStats{
+	HeapAlloc:  2 << 20,
+	HeapSys:    7680 << 10,
+	StackInuse: 256 << 10,
+	NextGC:     4 << 20,
+}
+
+func TestSummaryString(t *testing.T) {
+	s := memstats.FromMemStats(before)
+	got := s.String()
+	for _, want := range []string{
+		"HeapAlloc     1.5M\n",
+		"HeapSys       7.5M\n",
+		"StackInuse    512K\n",
+		"NextGC        4.0M\n",
+		"Alloc         0\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("\nwant line: %#v\n got summary:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "\n"); n != 18 {
+		t.Errorf("\nwant lines: 18\n got lines: %d", n)
+	}
+}
+
+func TestSummaryLine(t *testing.T) {
+	s := memstats.Summary{
+		{Name: "HeapAlloc", Value: bytefmt.New(1536 << 10)},
+		{Name: "HeapSys", Value: bytefmt.New(7680 << 10)},
+	}
+	if got, want := s.Line(), "HeapAlloc=1.5M HeapSys=7.5M"; got != want {
+		t.Errorf("\nwant line: %#v\n got line: %#v", want, got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	c := memstats.Diff(memstats.FromMemStats(before), memstats.FromMemStats(after))
+
+	want := "HeapAlloc   1.5M -> 2.0M (+512K)\n" +
+		"StackInuse  512K -> 256K (-256K)\n"
+	if got := c.String(); got != want {
+		t.Errorf("\nwant diff:\n%s\n got diff:\n%s", want, got)
+	}
+	if got, want := c.Line(), "HeapAlloc=+512K StackInuse=-256K"; got != want {
+		t.Errorf("\nwant line: %#v\n got line: %#v", want, got)
+	}
+}
+
+func TestDiffMissingFields(t *testing.T) {
+	c := memstats.Diff(memstats.FromMemStats(before), memstats.Summary{{Name: "NextGC"}})
+	if len(c) != 1 || c[0].Delta() != "-4.0M" {
+		t.Errorf("unexpected changes: %+v", c)
+	}
+}
+
+func TestFromMetrics(t *testing.T) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/memory/classes/total:bytes"},
+	}
+	metrics.Read(samples)
+	s := memstats.FromMetrics(samples)
+	if len(s) != 2 || s[0].Name != "/memory/classes/heap/objects:bytes" || s[1].Value.Value == 0 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+func TestReadMetrics(t *testing.T) {
+	s := memstats.ReadMetrics()
+	if v, ok := s.Get("/memory/classes/total:bytes"); !ok || v.Value == 0 {
+		t.Errorf("unexpected summary:\n%s", s)
+	}
+}
+
+func TestRead(t *testing.T) {
+	s := memstats.Read()
+	if v, ok := s.Get("HeapSys"); !ok || v.Value == 0 {
+		t.Errorf("unexpected summary:\n%s", s)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ErrSyntax indicates that a value does not have the right syntax for a size.
+var ErrSyntax = errors.New("invalid syntax")
+
+// ErrRange indicates that a value is out of range for a size.
+var ErrRange = errors.New("value out of range")
+
+// ParseError records a failed parse.
+type ParseError struct {
+	Input string // the input
+	Err   error  // the reason the conversion failed (e.g. ErrRange, ErrSyntax)
+}
+
+func (e *ParseError) Error() string {
+	return "bytefmt: parsing " + strconv.Quote(e.Input) + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Parse parses a size such as "10M", "1.5 GiB", "100Mbit" or "512",
+// the units of measure are those accepted by ParseUnit.
+// A fractional number of bytes is rounded to the nearest integer.
+func Parse(s string) (Bytes, error) {
+	v, err := parse(s)
+	if err != nil {
+		return Bytes{}, &ParseError{Input: s, Err: err}
+	}
+	return New(v), nil
+}
+
+func parse(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	num, unit := s[:i], strings.TrimSpace(s[i:])
+	u, ok := parseUnit(unit)
+	if !ok {
+		return 0, ErrSyntax
+	}
+	if u%B == 0 {
+		return scale(num, uint64(u/B))
+	}
+	v, err := scale(num, uint64(u))
+	if err != nil {
+		return 0, err
+	}
+	if v%uint64(B) >= uint64(B)/2 {
+		return v/uint64(B) + 1, nil
+	}
+	return v / uint64(B), nil
+}
+
+// scale returns the decimal number num multiplied by mult.
+func scale(num string, mult uint64) (uint64, error) {
+	whole, frac := num, ""
+	if i := strings.IndexByte(num, '.'); i != -1 {
+		whole, frac = num[:i], num[i+1:]
+	}
+	if whole == "" && frac == "" || strings.IndexByte(frac, '.') != -1 {
+		return 0, ErrSyntax
+	}
+	var w uint64
+	if whole != "" {
+		var err error
+		w, err = strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, ErrRange
+			}
+			return 0, ErrSyntax
+		}
+	}
+	hi, v := bits.Mul64(w, mult)
+	if hi != 0 {
+		return 0, ErrRange
+	}
+	if frac != "" {
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return 0, ErrSyntax
+		}
+		var carry uint64
+		v, carry = bits.Add64(v, uint64(math.Round(f*float64(mult))), 0)
+		if carry != 0 {
+			return 0, ErrRange
+		}
+	}
+	return v, nil
+}
+
+func pow10(n int) uint64 {
+	v := uint64(1)
+	for ; n > 0; n-- {
+		v *= 10
+	}
+	return v
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var parseTests = []struct {
+	name  string
+	line  string
+	input string
+	want  uint64
+	err   error
+	bench bool
+}{
+	{
+		name:  "bytes",
+		line:  testline(),
+		input: "512",
+		want:  512,
+	}, {
+		name:  "bytes unit",
+		line:  testline(),
+		input: "512B",
+		want:  512,
+	}, {
+		name:  "bytes word",
+		line:  testline(),
+		input: "512 bytes",
+		want:  512,
+	}, {
+		name:  "legacy name",
+		line:  testline(),
+		input: "10M",
+		want:  10 << 20,
+		bench: true,
+	}, {
+		name:  "lower case legacy name",
+		line:  testline(),
+		input: "512m",
+		want:  512 << 20,
+	}, {
+		name:  "binary unit",
+		line:  testline(),
+		input: "1.5 GiB",
+		want:  1610612736,
+	}, {
+		name:  "decimal unit",
+		line:  testline(),
+		input: "1.5GB",
+		want:  1500000000,
+	}, {
+		name:  "lower case decimal unit",
+		line:  testline(),
+		input: "2kb",
+		want:  2000,
+	}, {
+		name:  "fraction without whole part",
+		line:  testline(),
+		input: ".5K",
+		want:  512,
+	}, {
+		name:  "fractional bytes are rounded",
+		line:  testline(),
+		input: "1.5",
+		want:  2,
+	}, {
+		name:  "surrounding spaces",
+		line:  testline(),
+		input: "  7 E ",
+		want:  7 << 60,
+	}, {
+		name:  "bits",
+		line:  testline(),
+		input: "100Mbit",
+		want:  12500000,
+	}, {
+		name:  "binary bits",
+		line:  testline(),
+		input: "1 Kibit",
+		want:  128,
+	}, {
+		name:  "single bits are rounded",
+		line:  testline(),
+		input: "12bits",
+		want:  2,
+	}, {
+		name:  "largest",
+		line:  testline(),
+		input: "18446744073709551615",
+		want:  18446744073709551615,
+	}, {
+		name:  "empty",
+		line:  testline(),
+		input: "",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "unit only",
+		line:  testline(),
+		input: "K",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "unknown unit",
+		line:  testline(),
+		input: "1Z",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "two dots",
+		line:  testline(),
+		input: "1.2.3K",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "negative",
+		line:  testline(),
+		input: "-1K",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "too many bytes",
+		line:  testline(),
+		input: "18446744073709551616",
+		err:   bytefmt.ErrRange,
+	}, {
+		name:  "too many exabytes",
+		line:  testline(),
+		input: "16E",
+		err:   bytefmt.ErrRange,
+	},
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range parseTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := bytefmt.Parse(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if got.Value != tt.want {
+				t.Errorf("\nwant bytes: %d\n got bytes: %d\ntest: %s", tt.want, got.Value, tt.line)
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+
+	for _, tt := range parseTests {
+		if !tt.bench {
+			continue
+		}
+
+		b.Run(tt.line+"/"+tt.name+" "+tt.input, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = bytefmt.Parse(tt.input)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := bytefmt.Parse("1Z")
+	want := `bytefmt: parsing "1Z": invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Errorf("\nwant error: %#v\n got error: %v", want, err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math"
+	"strconv"
+)
+
+// Preset formats sizes exactly as a tool does.
+type Preset struct {
+	name   string
+	format func(v uint64) string
+}
+
+var (
+	// PgSizePretty formats as the pg_size_pretty function of PostgreSQL 15
+	// and later: bytes below 10240, then kB, MB, GB, TB or PB
+	// below 20480 of the unit, rounded half away from zero,
+	// e.g. 10239 bytes, 10 kB or 977 kB.
+	PgSizePretty = &Preset{name: "pg_size_pretty", format: pgSizePretty}
+
+	// Systemd formats as the format_bytes function of systemd:
+	// powers of 1024 with a truncated decimal, the suffix B only
+	// below 1024, e.g. 1023B, 1.4K or 3.0G. The largest size,
+	// for which format_bytes fails, is infinity as systemd writes it.
+	Systemd = &Preset{name: "systemd", format: systemdFormatBytes}
+
+	// Ls formats as ls -lh does, see Human.
+	Ls = &Preset{name: "ls -lh", format: Human}
+
+	// Du formats as du -h does, see Human.
+	Du = &Preset{name: "du -h", format: Human}
+)
+
+// String returns the name of the preset.
+func (p *Preset) String() string { return p.name }
+
+// Format formats b as the tool does.
+func (p *Preset) Format(b Bytes) string { return p.format(b.Value) }
+
+var pgSizePrettyUnits = []struct {
+	name     string
+	limit    uint64
+	round    bool
+	unitBits uint
+}{
+	{"bytes", 10 * 1024, false, 0},
+	{"kB", 20*1024 - 1, true, 10},
+	{"MB", 20*1024 - 1, true, 20},
+	{"GB", 20*1024 - 1, true, 30},
+	{"TB", 20*1024 - 1, true, 40},
+	{"PB", 20*1024 - 1, true, 50},
+}
+
+// pgSizePretty is pg_size_pretty of src/backend/utils/adt/dbsize.c.
+func pgSizePretty(size uint64) string {
+	for i, u := range pgSizePrettyUnits {
+		if i == len(pgSizePrettyUnits)-1 || size < u.limit {
+			if u.round {
+				size = size/2 + size%2 // half_rounded without overflow
+			}
+			return strconv.FormatUint(size, 10) + " " + u.name
+		}
+		// Shift a bit less if the next unit rounds
+		// and a bit more if this one does.
+		next := pgSizePrettyUnits[i+1]
+		bits := next.unitBits - u.unitBits
+		if next.round {
+			bits--
+		}
+		if u.round {
+			bits++
+		}
+		size >>= bits
+	}
+	panic("unreachable")
+}
+
+// systemdFormatBytes is format_bytes of src/basic/format-util.c.
+func systemdFormatBytes(t uint64) string {
+	if t == math.MaxUint64 {
+		return "infinity"
+	}
+	const suffixes = "KMGTPE"
+	for i := len(suffixes); i > 0; i-- {
+		factor := uint64(1) << (10 * uint(i))
+		if t < factor {
+			continue
+		}
+		var tenth uint64
+		if i > 1 {
+			tenth = t / (factor >> 10) * 10 / 1024 % 10
+		} else {
+			tenth = t * 10 / 1024 % 10
+		}
+		return strconv.FormatUint(t/factor, 10) + "." + strconv.FormatUint(tenth, 10) + suffixes[i-1:i]
+	}
+	return strconv.FormatUint(t, 10) + "B"
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// The expected output of pg_size_pretty is from src/test/regress/expected/dbsize.out
+// of PostgreSQL, that of format_bytes from src/test/test-format-util.c of systemd
+// and that of ls -lh and du -h --apparent-size from GNU coreutils.
+var presetTests = []struct {
+	name   string
+	line   string
+	preset *bytefmt.Preset
+	golden map[uint64]string
+}{
+	{
+		name:   "pg_size_pretty",
+		line:   testline(),
+		preset: bytefmt.PgSizePretty,
+		golden: map[uint64]string{
+			0:                 "0 bytes",
+			10:                "10 bytes",
+			1000:              "1000 bytes",
+			10239:             "10239 bytes",
+			10240:             "10 kB",
+			1000000:           "977 kB",
+			10485247:          "10239 kB",
+			10485248:          "10 MB",
+			1000000000:        "954 MB",
+			10736893951:       "10239 MB",
+			10736893952:       "10 GB",
+			1000000000000:     "931 GB",
+			10994579406847:    "10239 GB",
+			10994579406848:    "10 TB",
+			1000000000000000:  "909 TB",
+			11258449312612351: "10239 TB",
+			11258449312612352: "10 PB",
+			math.MaxInt64:     "8192 PB",
+		},
+	}, {
+		name:   "systemd",
+		line:   testline(),
+		preset: bytefmt.Systemd,
+		golden: map[uint64]string{
+			0:       "0B",
+			900:     "900B",
+			1023:    "1023B",
+			1024:    "1.0K",
+			1025:    "1.0K",
+			1100:    "1.0K",
+			1500:    "1.4K",
+			3 << 20: "3.0M",
+			3 << 30: "3.0G",
+			3 << 40: "3.0T",
+			3 << 50: "3.0P",
+			3 << 60: "3.0E",
+		},
+	}, {
+		name:   "ls -lh",
+		line:   testline(),
+		preset: bytefmt.Ls,
+		golden: map[uint64]string{
+			0:             "0",
+			1:             "1",
+			1023:          "1023",
+			1024:          "1.0K",
+			1025:          "1.1K",
+			1536:          "1.5K",
+			10239:         "10K",
+			10240:         "10K",
+			10241:         "11K",
+			1048575:       "1.0M",
+			1048576:       "1.0M",
+			1610612736:    "1.5G",
+			1099511627776: "1.0T",
+		},
+	}, {
+		name:   "du -h",
+		line:   testline(),
+		preset: bytefmt.Du,
+		golden: map[uint64]string{
+			1023:          "1023",
+			1025:          "1.1K",
+			10241:         "11K",
+			1048575:       "1.0M",
+			1610612736:    "1.5G",
+			1099511627776: "1.0T",
+		},
+	},
+}
+
+// The outputs for sizes the tools cannot format are extrapolated,
+// pg_size_pretty takes a bigint and format_bytes fails for the largest size.
+var presetExtrapolatedTests = []struct {
+	name   string
+	line   string
+	preset *bytefmt.Preset
+	size   uint64
+	want   string
+}{
+	{
+		name:   "pg_size_pretty beyond bigint",
+		line:   testline(),
+		preset: bytefmt.PgSizePretty,
+		size:   math.MaxUint64,
+		want:   "16384 PB",
+	}, {
+		name:   "systemd largest size",
+		line:   testline(),
+		preset: bytefmt.Systemd,
+		size:   math.MaxUint64,
+		want:   "infinity",
+	},
+}
+
+func TestPresetExtrapolated(t *testing.T) {
+	for _, tt := range presetExtrapolatedTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.preset.Format(bytefmt.New(tt.size)); got != tt.want {
+				t.Errorf("\nwant %s: %#v\n got %s: %#v\ntest: %s", tt.preset, tt.want, tt.preset, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestPreset(t *testing.T) {
+	for _, tt := range presetTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.preset.String() != tt.name {
+				t.Errorf("\nwant name: %#v\n got name: %#v\ntest: %s", tt.name, tt.preset.String(), tt.line)
+			}
+			for v, want := range tt.golden {
+				if got := tt.preset.Format(bytefmt.New(v)); got != want {
+					t.Errorf("\nwant %s %s: %#v\n got %s %s: %#v\ntest: %s",
+						tt.name, strconv.FormatUint(v, 10), want, tt.name, strconv.FormatUint(v, 10), got, tt.line)
+				}
+			}
+		})
+	}
+}
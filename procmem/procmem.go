@@ -0,0 +1,135 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package procmem reads memory usage and limits of the current process
+// from the Linux /proc file system and memory cgroups.
+package procmem
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pfmt/bytefmt"
+)
+
+// ErrNoCgroup is returned if the process is not in a memory cgroup.
+var ErrNoCgroup = errors.New("procmem: no memory cgroup")
+
+// Fields are the sizes of a /proc file by field name, e.g. "VmRSS".
+type Fields map[string]bytefmt.Bytes
+
+// Reader reads files relative to the Root directory, "/" if empty,
+// so it can be pointed at a fake root in tests.
+type Reader struct {
+	Root string
+}
+
+func (r Reader) path(name string) string {
+	root := r.Root
+	if root == "" {
+		root = "/"
+	}
+	return filepath.Join(root, filepath.FromSlash(name))
+}
+
+// Status returns the sizes in /proc/self/status, e.g. VmRSS and VmHWM.
+func (r Reader) Status() (Fields, error) {
+	return r.fields("proc/self/status")
+}
+
+// MemInfo returns the sizes in /proc/meminfo, e.g. MemTotal and MemAvailable.
+func (r Reader) MemInfo() (Fields, error) {
+	return r.fields("proc/meminfo")
+}
+
+// fields parses lines such as "VmRSS:	  312000 kB",
+// lines without the kB unit are skipped.
+func (r Reader) fields(name string) (Fields, error) {
+	b, err := os.ReadFile(r.path(name))
+	if err != nil {
+		return nil, err
+	}
+	f := make(Fields)
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		i := strings.IndexByte(s.Text(), ':')
+		if i == -1 {
+			continue
+		}
+		v := strings.Fields(s.Text()[i+1:])
+		if len(v) != 2 || v[1] != "kB" {
+			continue
+		}
+		n, err := bytefmt.Parse(v[0] + "K")
+		if err != nil {
+			return nil, &fs.PathError{Op: "parse", Path: r.path(name), Err: err}
+		}
+		f[s.Text()[:i]] = n
+	}
+	return f, s.Err()
+}
+
+// CgroupLimit returns the memory limit of the cgroup of the process,
+// the limit is false if the cgroup is unlimited ("max" in cgroup v2).
+func (r Reader) CgroupLimit() (bytefmt.Bytes, bool, error) {
+	return r.cgroup("memory.max", "memory.limit_in_bytes")
+}
+
+// CgroupUsage returns the memory usage of the cgroup of the process.
+func (r Reader) CgroupUsage() (bytefmt.Bytes, error) {
+	b, _, err := r.cgroup("memory.current", "memory.usage_in_bytes")
+	return b, err
+}
+
+// cgroup reads a cgroup v2 file or, failing that, a cgroup v1 file.
+func (r Reader) cgroup(v2, v1 string) (bytefmt.Bytes, bool, error) {
+	b, err := os.ReadFile(r.path("proc/self/cgroup"))
+	if err != nil {
+		return bytefmt.Bytes{}, false, err
+	}
+	var names []string
+	for _, line := range strings.Split(string(b), "\n") {
+		f := strings.SplitN(line, ":", 3)
+		if len(f) != 3 {
+			continue
+		}
+		if f[0] == "0" && f[1] == "" {
+			names = append(names, "sys/fs/cgroup"+f[2]+"/"+v2, "sys/fs/cgroup/"+v2)
+		}
+		for _, c := range strings.Split(f[1], ",") {
+			if c == "memory" {
+				names = append(names, "sys/fs/cgroup/memory"+f[2]+"/"+v1, "sys/fs/cgroup/memory/"+v1)
+			}
+		}
+	}
+	for _, name := range names {
+		b, err := os.ReadFile(r.path(name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return bytefmt.Bytes{}, false, err
+		}
+		s := strings.TrimSpace(string(b))
+		if s == "max" {
+			return bytefmt.Bytes{}, false, nil
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return bytefmt.Bytes{}, false, &fs.PathError{Op: "parse", Path: r.path(name), Err: err}
+		}
+		// cgroup v1 reports no limit as the largest page-aligned int64.
+		if n >= 1<<62 {
+			return bytefmt.Bytes{}, false, nil
+		}
+		return bytefmt.New(n), true, nil
+	}
+	return bytefmt.Bytes{}, false, ErrNoCgroup
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package procmem_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/pfmt/bytefmt/procmem"
+)
+
+const status = `Name:	app
+Umask:	0022
+State:	S (sleeping)
+VmPeak:	  1048576 kB
+VmHWM:	   327680 kB
+VmRSS:	   319488 kB
+Threads:	12
+`
+
+const meminfo = `MemTotal:       16384000 kB
+MemFree:         1024000 kB
+MemAvailable:    8192000 kB
+HugePages_Total:       0
+Hugepagesize:       2048 kB
+`
+
+func fakeRoot(t *testing.T, files map[string]string) procmem.Reader {
+	root := t.TempDir()
+	for name, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return procmem.Reader{Root: root}
+}
+
+func TestStatus(t *testing.T) {
+	r := fakeRoot(t, map[string]string{"proc/self/status": status})
+	f, err := r.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprintf("%v %v %v %d", f["VmRSS"], f["VmHWM"], f["VmPeak"], len(f))
+	if want := "312M 320M 1G 3"; got != want {
+		t.Errorf("\nwant status: %#v\n got status: %#v", want, got)
+	}
+}
+
+func TestMemInfo(t *testing.T) {
+	r := fakeRoot(t, map[string]string{"proc/meminfo": meminfo})
+	f, err := r.MemInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprintf("%.1f %.1f %v %d", f["MemTotal"], f["MemAvailable"], f["Hugepagesize"], len(f))
+	if want := "15.6G 7.8G 2M 4"; got != want {
+		t.Errorf("\nwant meminfo: %#v\n got meminfo: %#v", want, got)
+	}
+}
+
+var cgroupTests = []struct {
+	name    string
+	line    string
+	files   map[string]string
+	limit   string
+	limited bool
+	usage   string
+	err     error
+}{
+	{
+		name: "v2",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup":                               "0::/kubepods/pod1/app\n",
+			"sys/fs/cgroup/kubepods/pod1/app/memory.max":     "536870912\n",
+			"sys/fs/cgroup/kubepods/pod1/app/memory.current": "327155712\n",
+		},
+		limit:   "512M",
+		limited: true,
+		usage:   "312M",
+	}, {
+		name: "v2 namespace root",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup":             "0::/\n",
+			"sys/fs/cgroup/memory.max":     "max\n",
+			"sys/fs/cgroup/memory.current": "1048576\n",
+		},
+		limit: "0B",
+		usage: "1M",
+	}, {
+		name: "v2 path outside the namespace",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup":             "0::/../../app\n",
+			"sys/fs/cgroup/memory.max":     "268435456\n",
+			"sys/fs/cgroup/memory.current": "0\n",
+		},
+		limit:   "256M",
+		limited: true,
+		usage:   "0B",
+	}, {
+		name: "v1",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup": "12:cpu,cpuacct:/docker/abc\n" +
+				"11:memory:/docker/abc\n",
+			"sys/fs/cgroup/memory/docker/abc/memory.limit_in_bytes": "1073741824\n",
+			"sys/fs/cgroup/memory/docker/abc/memory.usage_in_bytes": "536870912\n",
+		},
+		limit:   "1G",
+		limited: true,
+		usage:   "512M",
+	}, {
+		name: "v1 unlimited",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup":                           "11:memory:/\n",
+			"sys/fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n",
+			"sys/fs/cgroup/memory/memory.usage_in_bytes": "2048\n",
+		},
+		limit: "0B",
+		usage: "2K",
+	}, {
+		name: "no cgroup",
+		line: testline(),
+		files: map[string]string{
+			"proc/self/cgroup": "0::/\n",
+		},
+		err: procmem.ErrNoCgroup,
+	},
+}
+
+func TestCgroup(t *testing.T) {
+	for _, tt := range cgroupTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := fakeRoot(t, tt.files)
+			limit, limited, err := r.CgroupLimit()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if err != nil {
+				return
+			}
+			if got := limit.String(); got != tt.limit || limited != tt.limited {
+				t.Errorf("\nwant limit: %s %t\n got limit: %s %t\ntest: %s", tt.limit, tt.limited, got, limited, tt.line)
+			}
+			usage, err := r.CgroupUsage()
+			if err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if got := usage.String(); got != tt.usage {
+				t.Errorf("\nwant usage: %s\n got usage: %s\ntest: %s", tt.usage, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestStatusNotExist(t *testing.T) {
+	r := fakeRoot(t, nil)
+	if _, err := r.Status(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("\nwant error: %v\n got error: %v", os.ErrNotExist, err)
+	}
+}
+
+func testline() string {
+	_, file, line, ok := runtime.Caller(1)
+	if ok {
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	return "it was not possible to recover file and line number information about function invocations"
+}
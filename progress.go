@@ -0,0 +1,146 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress renders the progress of a transfer, e.g.
+//
+//	[=====>    ] 512M / 1.5G  12.3M/s  ETA 1m20s
+//
+// On a terminal the line is redrawn in place with carriage returns,
+// on other writers a line is logged at most once per interval.
+// Progress is an io.Writer counting the bytes written to it,
+// so it can be used with io.TeeReader or io.MultiWriter.
+type Progress struct {
+	counter
+	w     io.Writer
+	total uint64
+
+	// Width is the width of the bar, a zero width hides the bar.
+	Width int
+	// Interval is the minimum time between redraws,
+	// 200ms on a terminal and 10s on other writers by default.
+	Interval time.Duration
+	// TTY reports whether the line is redrawn with carriage returns,
+	// it is detected by NewProgress.
+	TTY bool
+
+	mu   sync.Mutex
+	last time.Time
+	n    int // length of the last line drawn on a terminal
+}
+
+// NewProgress returns a progress writing to w, the total is zero if unknown.
+func NewProgress(w io.Writer, total uint64) *Progress {
+	p := &Progress{
+		counter:  newCounter(),
+		w:        w,
+		total:    total,
+		Width:    20,
+		Interval: 10 * time.Second,
+		TTY:      isTerminal(w),
+	}
+	if p.TTY {
+		p.Interval = 200 * time.Millisecond
+	}
+	return p
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Write counts the bytes of b and redraws the progress if due.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.Add(len(b))
+	return len(b), nil
+}
+
+// Add counts n bytes and redraws the progress if due.
+func (p *Progress) Add(n int) {
+	p.add(n)
+	p.draw(false)
+}
+
+// Finish draws the final progress, ending the line on a terminal.
+func (p *Progress) Finish() {
+	p.draw(true)
+	if p.TTY {
+		p.mu.Lock()
+		_, _ = io.WriteString(p.w, "\n")
+		p.mu.Unlock()
+	}
+}
+
+func (p *Progress) draw(force bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if !force && now.Sub(p.last) < p.Interval {
+		return
+	}
+	p.last = now
+	s := p.String()
+	if p.TTY {
+		n := len(s)
+		if n < p.n {
+			s += strings.Repeat(" ", p.n-n)
+		}
+		p.n = n
+		_, _ = io.WriteString(p.w, "\r"+s)
+	} else {
+		_, _ = io.WriteString(p.w, s+"\n")
+	}
+}
+
+// String returns the current progress line.
+func (p *Progress) String() string {
+	var s strings.Builder
+	done := p.Count()
+	if p.total != 0 && p.Width > 0 {
+		f := p.Width
+		if done < p.total {
+			f = int(float64(done) / float64(p.total) * float64(p.Width))
+		}
+		s.WriteByte('[')
+		s.WriteString(strings.Repeat("=", f))
+		if f < p.Width {
+			s.WriteByte('>')
+			s.WriteString(strings.Repeat(" ", p.Width-f-1))
+		}
+		s.WriteString("] ")
+	}
+	s.WriteString(New(done).short())
+	if p.total != 0 {
+		s.WriteString(" / ")
+		s.WriteString(New(p.total).short())
+	}
+	rate := p.Rate()
+	s.WriteString("  ")
+	s.WriteString(rate.String())
+	if p.total != 0 && done < p.total {
+		s.WriteString("  ETA ")
+		if rate > 0 {
+			eta := time.Duration(float64(p.total-done) / float64(rate) * float64(time.Second))
+			s.WriteString(eta.Round(time.Second).String())
+		} else {
+			s.WriteString("-")
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var progressTests = []struct {
+	name    string
+	line    string
+	total   uint64
+	width   int
+	done    int
+	elapsed time.Duration
+	want    string
+}{
+	{
+		name:  "start",
+		line:  testline(),
+		total: 1536 << 20,
+		width: 10,
+		want:  "[>         ] 0B / 1.5G  0B/s  ETA -",
+	}, {
+		name:    "transfer",
+		line:    testline(),
+		total:   1536 << 20,
+		width:   20,
+		done:    512 << 20,
+		elapsed: 40 * time.Second,
+		want:    "[======>             ] 512M / 1.5G  12.8M/s  ETA 1m20s",
+	}, {
+		name:    "done",
+		line:    testline(),
+		total:   1 << 20,
+		width:   10,
+		done:    1 << 20,
+		elapsed: 4 * time.Second,
+		want:    "[==========] 1M / 1M  256K/s",
+	}, {
+		name:    "no bar",
+		line:    testline(),
+		total:   1 << 30,
+		done:    1 << 29,
+		elapsed: time.Second,
+		want:    "512M / 1G  512M/s  ETA 1s",
+	}, {
+		name:    "unknown total",
+		line:    testline(),
+		width:   10,
+		done:    1536,
+		elapsed: time.Second,
+		want:    "1.5K  1.5K/s",
+	},
+}
+
+func TestProgressString(t *testing.T) {
+	for _, tt := range progressTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Unix(0, 0)
+			p := bytefmt.NewProgress(io.Discard, tt.total)
+			p.SetClock(func() time.Time { return now })
+			p.Width = tt.width
+			now = now.Add(tt.elapsed)
+			p.Add(tt.done)
+
+			got := p.String()
+			if got != tt.want {
+				t.Errorf("\nwant progress: %#v\n got progress: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestProgressLog(t *testing.T) {
+	now := time.Unix(0, 0)
+	var buf bytes.Buffer
+	p := bytefmt.NewProgress(&buf, 4<<20)
+	p.SetClock(func() time.Time { return now })
+	p.Width = 4
+
+	if p.TTY {
+		t.Fatal("want no TTY for a buffer")
+	}
+	for i := 0; i < 4; i++ {
+		now = now.Add(5 * time.Second)
+		_, _ = io.Copy(p, strings.NewReader(strings.Repeat("x", 1<<20)))
+	}
+	p.Finish()
+
+	want := "[=>  ] 1M / 4M  204.8K/s  ETA 15s\n" +
+		"[===>] 3M / 4M  204.8K/s  ETA 5s\n" +
+		"[====] 4M / 4M  204.8K/s\n"
+	if buf.String() != want {
+		t.Errorf("\nwant log: %#v\n got log: %#v", want, buf.String())
+	}
+}
+
+func TestProgressTTY(t *testing.T) {
+	now := time.Unix(0, 0)
+	var buf bytes.Buffer
+	p := bytefmt.NewProgress(&buf, 0)
+	p.SetClock(func() time.Time { return now })
+	p.TTY = true
+	p.Interval = time.Second
+
+	now = now.Add(time.Second)
+	p.Add(1 << 20)
+	now = now.Add(time.Second)
+	p.Add(1)
+	p.Finish()
+
+	want := "\r1M  1M/s" +
+		"\r1M  512K/s" +
+		"\r1M  512K/s" +
+		"\n"
+	if buf.String() != want {
+		t.Errorf("\nwant terminal: %#v\n got terminal: %#v", want, buf.String())
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// QuantityFormat is the format of a Kubernetes resource quantity.
+type QuantityFormat int
+
+const (
+	DecimalSI       QuantityFormat = iota // e.g. 129M, 1G or 1500
+	BinarySI                              // e.g. 512Mi or 1536
+	DecimalExponent                       // e.g. 1e9 or 129e6
+)
+
+// Quantity is a size in the grammar of the Kubernetes resource quantities
+// such as 512Mi, 1G, 1e9 or 129M, as used for memory and storage.
+type Quantity struct {
+	Bytes  Bytes
+	Format QuantityFormat
+}
+
+// NewQuantity returns the quantity of b in the format.
+func NewQuantity(b Bytes, f QuantityFormat) Quantity {
+	return Quantity{Bytes: b, Format: f}
+}
+
+var (
+	quantityDecimal = []string{"k", "M", "G", "T", "P", "E"}
+	quantityBinary  = []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+)
+
+// ParseQuantity parses a Kubernetes quantity, the format is that of
+// the suffix: BinarySI for Ki, Mi and so on, DecimalExponent for e3, E6
+// and so on and DecimalSI otherwise, including the suffixes n, u and m.
+// Fractional numbers of bytes are rounded up as resource.Quantity.Value does.
+func ParseQuantity(s string) (Quantity, error) {
+	q, err := parseQuantity(s)
+	if err != nil {
+		return Quantity{}, &ParseError{Input: s, Err: err}
+	}
+	return q, nil
+}
+
+func parseQuantity(s string) (Quantity, error) {
+	num := s
+	neg := false
+	if num != "" && (num[0] == '+' || num[0] == '-') {
+		neg = num[0] == '-'
+		num = num[1:]
+	}
+	i := 0
+	for i < len(num) && isDigit(num[i]) {
+		i++
+	}
+	whole := num[:i]
+	frac := ""
+	if i < len(num) && num[i] == '.' {
+		j := i + 1
+		for j < len(num) && isDigit(num[j]) {
+			j++
+		}
+		frac, i = num[i+1:j], j
+	}
+	suffix := num[i:]
+	if whole+frac == "" {
+		return Quantity{}, ErrSyntax
+	}
+
+	// The value is digits * base^exp / 10^len(frac).
+	f := DecimalSI
+	base, exp := int64(10), 0
+	if p := indexString(quantityBinary, suffix); p != -1 {
+		f, base, exp = BinarySI, 1024, p+1
+	} else if p := indexString(quantityDecimal, suffix); p != -1 {
+		exp = 3 * (p + 1)
+	} else if p := strings.Index("num", suffix); len(suffix) == 1 && p != -1 {
+		exp = -3 * (3 - p)
+	} else if len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		n, err := strconv.Atoi(suffix[1:])
+		if err != nil || suffix[1] != '-' && suffix[1] != '+' && !isDigit(suffix[1]) {
+			return Quantity{}, ErrSyntax
+		}
+		f, exp = DecimalExponent, n
+	} else if suffix != "" {
+		return Quantity{}, ErrSyntax
+	}
+
+	digits, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		return Quantity{}, ErrSyntax
+	}
+	if digits.Sign() == 0 {
+		return Quantity{Format: f}, nil
+	}
+	if neg {
+		return Quantity{}, ErrRange
+	}
+	// Avoid huge numbers, 10^-40 rounds up to 1 and 10^40 is out of range.
+	if exp > 40 {
+		return Quantity{}, ErrRange
+	}
+	if exp < -40 {
+		return Quantity{Bytes: New(1), Format: f}, nil
+	}
+	den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(frac))), nil)
+	if exp >= 0 {
+		digits.Mul(digits, new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(exp)), nil))
+	} else {
+		den.Mul(den, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	}
+	v, r := digits.QuoRem(digits, den, new(big.Int))
+	if r.Sign() != 0 {
+		v.Add(v, big.NewInt(1))
+	}
+	if !v.IsUint64() {
+		return Quantity{}, ErrRange
+	}
+	return Quantity{Bytes: New(v.Uint64()), Format: f}, nil
+}
+
+func indexString(s []string, x string) int {
+	for i, v := range s {
+		if v == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// String returns the canonical form of the quantity as kubectl displays it.
+// BinarySI quantities less than 1024 are formatted as DecimalSI,
+// others with the largest binary suffix dividing them exactly, e.g. 1536Mi;
+// decimal quantities with the largest exponent multiple of 3
+// leaving an integer, e.g. 129M, 1500 or 1e9.
+func (q Quantity) String() string {
+	v := q.Bytes.Value
+	if v == 0 {
+		return "0"
+	}
+	f := q.Format
+	if f == BinarySI && v < 1024 {
+		f = DecimalSI
+	}
+	if f == BinarySI {
+		p := 0
+		for v%1024 == 0 {
+			v /= 1024
+			p++
+		}
+		s := strconv.FormatUint(v, 10)
+		if p > 0 {
+			s += quantityBinary[p-1]
+		}
+		return s
+	}
+	exp := 0
+	for v%10 == 0 {
+		v /= 10
+		exp++
+	}
+	for exp%3 != 0 {
+		v *= 10
+		exp--
+	}
+	s := strconv.FormatUint(v, 10)
+	switch {
+	case exp == 0:
+		return s
+	case f == DecimalExponent:
+		return s + "e" + strconv.Itoa(exp)
+	}
+	return s + quantityDecimal[exp/3-1]
+}
@@ -0,0 +1,262 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var parseQuantityTests = []struct {
+	name   string
+	line   string
+	in     string
+	bytes  uint64
+	format bytefmt.QuantityFormat
+	want   string
+	err    error
+}{
+	{
+		name:   "binary",
+		line:   testline(),
+		in:     "512Mi",
+		bytes:  512 << 20,
+		format: bytefmt.BinarySI,
+		want:   "512Mi",
+	}, {
+		name:   "decimal",
+		line:   testline(),
+		in:     "1G",
+		bytes:  1000000000,
+		format: bytefmt.DecimalSI,
+		want:   "1G",
+	}, {
+		name:   "exponent",
+		line:   testline(),
+		in:     "1e9",
+		bytes:  1000000000,
+		format: bytefmt.DecimalExponent,
+		want:   "1e9",
+	}, {
+		name:   "exponent multiple of three",
+		line:   testline(),
+		in:     "12E5",
+		bytes:  1200000,
+		format: bytefmt.DecimalExponent,
+		want:   "1200e3",
+	}, {
+		name:   "mega",
+		line:   testline(),
+		in:     "129M",
+		bytes:  129000000,
+		format: bytefmt.DecimalSI,
+		want:   "129M",
+	}, {
+		name:   "binary fraction",
+		line:   testline(),
+		in:     "1.5Gi",
+		bytes:  1610612736,
+		format: bytefmt.BinarySI,
+		want:   "1536Mi",
+	}, {
+		name:   "decimal fraction",
+		line:   testline(),
+		in:     "1.5G",
+		bytes:  1500000000,
+		format: bytefmt.DecimalSI,
+		want:   "1500M",
+	}, {
+		name:   "binary not divisible",
+		line:   testline(),
+		in:     "1000.5Ki",
+		bytes:  1024512,
+		format: bytefmt.BinarySI,
+		want:   "1024512",
+	}, {
+		name:   "binary less than 1024",
+		line:   testline(),
+		in:     "0.5Ki",
+		bytes:  512,
+		format: bytefmt.BinarySI,
+		want:   "512",
+	}, {
+		name:   "binary reduced",
+		line:   testline(),
+		in:     "2048Ki",
+		bytes:  2 << 20,
+		format: bytefmt.BinarySI,
+		want:   "2Mi",
+	}, {
+		name:   "plain",
+		line:   testline(),
+		in:     "+1500",
+		bytes:  1500,
+		format: bytefmt.DecimalSI,
+		want:   "1500",
+	}, {
+		name:   "milli rounded up",
+		line:   testline(),
+		in:     "100m",
+		bytes:  1,
+		format: bytefmt.DecimalSI,
+		want:   "1",
+	}, {
+		name:   "fraction rounded up",
+		line:   testline(),
+		in:     "1.0001k",
+		bytes:  1001,
+		format: bytefmt.DecimalSI,
+		want:   "1001",
+	}, {
+		name:   "tiny",
+		line:   testline(),
+		in:     "1e-100",
+		bytes:  1,
+		format: bytefmt.DecimalExponent,
+		want:   "1",
+	}, {
+		name:   "zero",
+		line:   testline(),
+		in:     "-0.0Gi",
+		format: bytefmt.BinarySI,
+		want:   "0",
+	}, {
+		name:   "largest",
+		line:   testline(),
+		in:     "18446744073709551615",
+		bytes:  18446744073709551615,
+		format: bytefmt.DecimalSI,
+		want:   "18446744073709551615",
+	}, {
+		name:   "exa",
+		line:   testline(),
+		in:     "10E",
+		bytes:  10000000000000000000,
+		format: bytefmt.DecimalSI,
+		want:   "10E",
+	}, {
+		name: "negative",
+		line: testline(),
+		in:   "-1Gi",
+		err:  bytefmt.ErrRange,
+	}, {
+		name: "too large",
+		line: testline(),
+		in:   "16Ei",
+		err:  bytefmt.ErrRange,
+	}, {
+		name: "huge exponent",
+		line: testline(),
+		in:   "1e1000000000",
+		err:  bytefmt.ErrRange,
+	}, {
+		name: "upper case kilo",
+		line: testline(),
+		in:   "1K",
+		err:  bytefmt.ErrSyntax,
+	}, {
+		name: "blank",
+		line: testline(),
+		in:   "1 Gi",
+		err:  bytefmt.ErrSyntax,
+	}, {
+		name: "no number",
+		line: testline(),
+		in:   "Gi",
+		err:  bytefmt.ErrSyntax,
+	}, {
+		name: "no exponent",
+		line: testline(),
+		in:   "1e",
+		err:  bytefmt.ErrSyntax,
+	}, {
+		name: "bytes suffix",
+		line: testline(),
+		in:   "1GB",
+		err:  bytefmt.ErrSyntax,
+	},
+}
+
+func TestParseQuantity(t *testing.T) {
+	for _, tt := range parseQuantityTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.in, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := bytefmt.ParseQuantity(tt.in)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if err != nil {
+				return
+			}
+			if q.Bytes.Value != tt.bytes || q.Format != tt.format {
+				t.Errorf("\nwant quantity: %d %d\n got quantity: %d %d\ntest: %s", tt.bytes, tt.format, q.Bytes.Value, q.Format, tt.line)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("\nwant string: %#v\n got string: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+			if r, err := bytefmt.ParseQuantity(q.String()); err != nil || r.Bytes.Value != q.Bytes.Value {
+				t.Errorf("\nround trip of %s: %d, %v\ntest: %s", q, r.Bytes.Value, err, tt.line)
+			}
+		})
+	}
+}
+
+var quantityStringTests = []struct {
+	name   string
+	line   string
+	bytes  uint64
+	format bytefmt.QuantityFormat
+	want   string
+}{
+	{
+		name:   "binary less than 1024",
+		line:   testline(),
+		bytes:  1000,
+		format: bytefmt.BinarySI,
+		want:   "1k",
+	}, {
+		name:   "binary",
+		line:   testline(),
+		bytes:  1 << 63,
+		format: bytefmt.BinarySI,
+		want:   "8Ei",
+	}, {
+		name:   "decimal of binary value",
+		line:   testline(),
+		bytes:  1 << 30,
+		format: bytefmt.DecimalSI,
+		want:   "1073741824",
+	}, {
+		name:   "kilo",
+		line:   testline(),
+		bytes:  1200000,
+		format: bytefmt.DecimalSI,
+		want:   "1200k",
+	}, {
+		name:   "exponent",
+		line:   testline(),
+		bytes:  5000,
+		format: bytefmt.DecimalExponent,
+		want:   "5e3",
+	},
+}
+
+func TestQuantityString(t *testing.T) {
+	for _, tt := range quantityStringTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := bytefmt.NewQuantity(bytefmt.New(tt.bytes), tt.format).String()
+			if got != tt.want {
+				t.Errorf("\nwant string: %#v\n got string: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsatisfiable is returned by ParseRange if none of the ranges
+// overlaps the content.
+var ErrUnsatisfiable = errors.New("bytefmt: range not satisfiable")
+
+// ByteRange is an inclusive range of byte positions.
+type ByteRange struct {
+	First Bytes
+	Last  Bytes
+}
+
+// Length returns the number of bytes in the range,
+// saturated at the largest size.
+func (r ByteRange) Length() Bytes {
+	return r.First.with(r.Last.Value - r.First.Value).AddSat(New(1))
+}
+
+// String returns the range in human form with the end exclusive,
+// e.g. 1M–2M for the bytes 1048576-2097151.
+// The end saturates at the largest size.
+func (r ByteRange) String() string {
+	return r.First.short() + "–" + r.Last.AddSat(New(1)).short()
+}
+
+// ParseRange parses a Range header such as "bytes=0-499, -500"
+// for content of the size, resolving open and suffix ranges.
+// Ranges beyond the end of the content are ignored, ErrUnsatisfiable
+// is returned if none is left.
+func ParseRange(header string, size uint64) ([]ByteRange, error) {
+	specs, ok := cutPrefix(header, "bytes=")
+	if !ok {
+		return nil, &ParseError{Input: header, Err: ErrSyntax}
+	}
+	var ranges []ByteRange
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		i := strings.IndexByte(spec, '-')
+		if i == -1 {
+			return nil, &ParseError{Input: header, Err: ErrSyntax}
+		}
+		first, last := spec[:i], spec[i+1:]
+		var r ByteRange
+		if first == "" {
+			n, err := parseRangePos(header, last)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = ByteRange{First: New(size - n), Last: New(size - 1)}
+		} else {
+			f, err := parseRangePos(header, first)
+			if err != nil {
+				return nil, err
+			}
+			l := uint64(0)
+			if last == "" {
+				l = size - 1
+			} else if l, err = parseRangePos(header, last); err != nil {
+				return nil, err
+			} else if l < f {
+				return nil, &ParseError{Input: header, Err: ErrSyntax}
+			}
+			if f >= size {
+				continue
+			}
+			if l >= size {
+				l = size - 1
+			}
+			r = ByteRange{First: New(f), Last: New(l)}
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, ErrUnsatisfiable
+	}
+	return ranges, nil
+}
+
+func parseRangePos(header, s string) (uint64, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, &ParseError{Input: header, Err: ErrSyntax}
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, &ParseError{Input: header, Err: ErrRange}
+		}
+		return 0, &ParseError{Input: header, Err: ErrSyntax}
+	}
+	return n, nil
+}
+
+// ContentRange is a Content-Range header.
+type ContentRange struct {
+	Range       ByteRange
+	Size        Bytes // complete length of the content
+	SizeKnown   bool  // false for "bytes 0-499/*"
+	Unsatisfied bool  // true for "bytes */1234"
+}
+
+// ParseContentRange parses a Content-Range header such as
+// "bytes 0-499/1234", "bytes 0-499/*" or "bytes */1234",
+// validating the range against the complete length if it is known.
+func ParseContentRange(header string) (ContentRange, error) {
+	var c ContentRange
+	s, ok := cutPrefix(header, "bytes ")
+	i := strings.IndexByte(s, '/')
+	if !ok || i == -1 {
+		return c, &ParseError{Input: header, Err: ErrSyntax}
+	}
+	rng, size := s[:i], s[i+1:]
+	if size != "*" {
+		n, err := parseRangePos(header, size)
+		if err != nil {
+			return c, err
+		}
+		c.Size, c.SizeKnown = New(n), true
+	}
+	if rng == "*" {
+		if !c.SizeKnown {
+			return c, &ParseError{Input: header, Err: ErrSyntax}
+		}
+		c.Unsatisfied = true
+		return c, nil
+	}
+	j := strings.IndexByte(rng, '-')
+	if j == -1 {
+		return c, &ParseError{Input: header, Err: ErrSyntax}
+	}
+	first, err := parseRangePos(header, rng[:j])
+	if err != nil {
+		return c, err
+	}
+	last, err := parseRangePos(header, rng[j+1:])
+	if err != nil {
+		return c, err
+	}
+	if last < first || c.SizeKnown && last >= c.Size.Value {
+		return c, &ParseError{Input: header, Err: ErrRange}
+	}
+	c.Range = ByteRange{First: New(first), Last: New(last)}
+	return c, nil
+}
+
+// String returns the header in human form,
+// e.g. "1M–2M of 10M" or "unsatisfied of 10M".
+func (c ContentRange) String() string {
+	var s string
+	if c.Unsatisfied {
+		s = "unsatisfied"
+	} else {
+		s = c.Range.String()
+	}
+	if c.SizeKnown {
+		return s + " of " + c.Size.short()
+	}
+	return s + " of unknown size"
+}
+
+// Header returns the Content-Range header.
+func (c ContentRange) Header() string {
+	s := "bytes "
+	if c.Unsatisfied {
+		s += "*"
+	} else {
+		s += strconv.FormatUint(c.Range.First.Value, 10) + "-" + strconv.FormatUint(c.Range.Last.Value, 10)
+	}
+	if c.SizeKnown {
+		return s + "/" + strconv.FormatUint(c.Size.Value, 10)
+	}
+	return s + "/*"
+}
+
+// cutPrefix is strings.CutPrefix which requires Go 1.20.
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,226 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var parseRangeTests = []struct {
+	name   string
+	line   string
+	header string
+	size   uint64
+	want   string
+	err    error
+}{
+	{
+		name:   "closed",
+		line:   testline(),
+		header: "bytes=1048576-2097151",
+		size:   10 << 20,
+		want:   "1048576-2097151 1M–2M",
+	}, {
+		name:   "open",
+		line:   testline(),
+		header: "bytes=9437184-",
+		size:   10 << 20,
+		want:   "9437184-10485759 9M–10M",
+	}, {
+		name:   "suffix",
+		line:   testline(),
+		header: "bytes=-512",
+		size:   1536,
+		want:   "1024-1535 1K–1.5K",
+	}, {
+		name:   "suffix longer than content",
+		line:   testline(),
+		header: "bytes=-4096",
+		size:   1024,
+		want:   "0-1023 0B–1K",
+	}, {
+		name:   "last beyond the end",
+		line:   testline(),
+		header: "bytes=0-999999",
+		size:   2048,
+		want:   "0-2047 0B–2K",
+	}, {
+		name:   "multiple",
+		line:   testline(),
+		header: "bytes=0-1023, 4096-8191,-1024",
+		size:   1 << 20,
+		want:   "0-1023 0B–1K,4096-8191 4K–8K,1047552-1048575 1023K–1M",
+	}, {
+		name:   "unsatisfiable ignored",
+		line:   testline(),
+		header: "bytes=0-1, 5000-",
+		size:   4096,
+		want:   "0-1 0B–2B",
+	}, {
+		name:   "unsatisfiable",
+		line:   testline(),
+		header: "bytes=4096-",
+		size:   4096,
+		err:    bytefmt.ErrUnsatisfiable,
+	}, {
+		name:   "empty suffix",
+		line:   testline(),
+		header: "bytes=-0",
+		size:   4096,
+		err:    bytefmt.ErrUnsatisfiable,
+	}, {
+		name:   "empty content",
+		line:   testline(),
+		header: "bytes=-10",
+		err:    bytefmt.ErrUnsatisfiable,
+	}, {
+		name:   "unit",
+		line:   testline(),
+		header: "items=0-1",
+		size:   4096,
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "reversed",
+		line:   testline(),
+		header: "bytes=10-5",
+		size:   4096,
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "sign",
+		line:   testline(),
+		header: "bytes=+1-5",
+		size:   4096,
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "no dash",
+		line:   testline(),
+		header: "bytes=10",
+		size:   4096,
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "too large",
+		line:   testline(),
+		header: "bytes=0-18446744073709551616",
+		size:   4096,
+		err:    bytefmt.ErrRange,
+	},
+}
+
+func TestParseRange(t *testing.T) {
+	for _, tt := range parseRangeTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.header, func(t *testing.T) {
+			t.Parallel()
+
+			ranges, err := bytefmt.ParseRange(tt.header, tt.size)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			var got []string
+			for _, r := range ranges {
+				got = append(got, strconv.FormatUint(r.First.Value, 10)+"-"+strconv.FormatUint(r.Last.Value, 10)+" "+r.String())
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("\nwant ranges: %#v\n got ranges: %#v\ntest: %s", tt.want, strings.Join(got, ","), tt.line)
+			}
+		})
+	}
+}
+
+func TestByteRangeLength(t *testing.T) {
+	r := bytefmt.ByteRange{First: bytefmt.New(1 << 20), Last: bytefmt.New(2<<20 - 1)}
+	if got := r.Length(); got.Value != 1<<20 {
+		t.Errorf("\nwant length: %d\n got length: %d", 1<<20, got.Value)
+	}
+}
+
+func TestByteRangeLargest(t *testing.T) {
+	r := bytefmt.ByteRange{First: bytefmt.New(0), Last: bytefmt.New(math.MaxUint64)}
+	if got := r.Length(); got.Value != math.MaxUint64 {
+		t.Errorf("\nwant length: %d\n got length: %d", uint64(math.MaxUint64), got.Value)
+	}
+	if got := r.String(); got != "0B–16E" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "0B–16E", got)
+	}
+}
+
+var parseContentRangeTests = []struct {
+	name   string
+	line   string
+	header string
+	want   string
+	err    error
+}{
+	{
+		name:   "known size",
+		line:   testline(),
+		header: "bytes 1048576-2097151/10485760",
+		want:   "1M–2M of 10M",
+	}, {
+		name:   "unknown size",
+		line:   testline(),
+		header: "bytes 0-511/*",
+		want:   "0B–512B of unknown size",
+	}, {
+		name:   "unsatisfied",
+		line:   testline(),
+		header: "bytes */1536",
+		want:   "unsatisfied of 1.5K",
+	}, {
+		name:   "last beyond the end",
+		line:   testline(),
+		header: "bytes 0-1024/1024",
+		err:    bytefmt.ErrRange,
+	}, {
+		name:   "reversed",
+		line:   testline(),
+		header: "bytes 10-5/1024",
+		err:    bytefmt.ErrRange,
+	}, {
+		name:   "unsatisfied unknown size",
+		line:   testline(),
+		header: "bytes */*",
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "no size",
+		line:   testline(),
+		header: "bytes 0-1",
+		err:    bytefmt.ErrSyntax,
+	}, {
+		name:   "unit",
+		line:   testline(),
+		header: "items 0-1/2",
+		err:    bytefmt.ErrSyntax,
+	},
+}
+
+func TestParseContentRange(t *testing.T) {
+	for _, tt := range parseContentRangeTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.header, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := bytefmt.ParseContentRange(tt.header)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if err != nil {
+				return
+			}
+			if c.String() != tt.want {
+				t.Errorf("\nwant string: %#v\n got string: %#v\ntest: %s", tt.want, c.String(), tt.line)
+			}
+			if c.Header() != tt.header {
+				t.Errorf("\nwant header: %#v\n got header: %#v\ntest: %s", tt.header, c.Header(), tt.line)
+			}
+		})
+	}
+}
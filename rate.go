@@ -0,0 +1,189 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate is a transfer rate in bytes per second.
+type Rate float64
+
+// Bytes returns the number of bytes per second rounded to an integer.
+func (r Rate) Bytes() Bytes {
+	if r <= 0 || math.IsNaN(float64(r)) {
+		return New(0)
+	}
+	if r >= math.MaxUint64 {
+		return New(math.MaxUint64)
+	}
+	return New(uint64(math.Round(float64(r))))
+}
+
+// String returns the rate with at most one decimal, e.g. 12.3M/s.
+func (r Rate) String() string {
+	return r.Bytes().short() + "/s"
+}
+
+// Format formats the rate as Bytes does followed by "/s",
+// the width and the quotes apply to the whole rate, e.g. "1.5K/s".
+func (r Rate) Format(f fmt.State, c rune) {
+	vf := "%"
+	for _, flag := range "+ " {
+		if f.Flag(int(flag)) {
+			vf += string(flag)
+		}
+	}
+	if f.Flag('#') && c != 'q' {
+		vf += "#"
+	}
+	if p, ok := f.Precision(); ok {
+		vf += "." + strconv.Itoa(p)
+	}
+	if c == 'q' {
+		vf += "s"
+	} else {
+		vf += string(c)
+	}
+	s := fmt.Sprintf(vf, r.Bytes()) + "/s"
+	if c == 'q' {
+		if f.Flag('#') {
+			s = fmt.Sprintf("%#q", s)
+		} else {
+			s = strconv.Quote(s)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		pf := "%"
+		if f.Flag('-') {
+			pf += "-"
+		} else if f.Flag('0') {
+			pf += "0"
+		}
+		s = fmt.Sprintf(pf+strconv.Itoa(w)+"s", s)
+	}
+	_, _ = io.WriteString(f, s)
+}
+
+// Estimator estimates a transfer rate from byte counts and timestamps.
+type Estimator interface {
+	// Add records n bytes transferred since the previous call at the time t.
+	Add(n uint64, t time.Time)
+	// Rate returns the estimated rate.
+	Rate() Rate
+}
+
+// EWMA is an exponentially weighted moving average of a transfer rate,
+// safe for concurrent use. The weight of a sample halves every half-life.
+// The first call to Add only starts the clock,
+// add zero counts periodically to let the rate decay during stalls.
+type EWMA struct {
+	halfLife time.Duration
+
+	mu      sync.Mutex
+	rate    float64
+	last    time.Time
+	pending uint64
+	started bool
+	ready   bool
+}
+
+func NewEWMA(halfLife time.Duration) *EWMA {
+	return &EWMA{halfLife: halfLife}
+}
+
+func (e *EWMA) Add(n uint64, t time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started {
+		e.started, e.last = true, t
+		return
+	}
+	e.pending += n
+	dt := t.Sub(e.last)
+	if dt <= 0 {
+		return
+	}
+	r := float64(e.pending) / dt.Seconds()
+	if !e.ready || e.halfLife <= 0 {
+		e.rate, e.ready = r, true
+	} else {
+		alpha := 1 - math.Exp(-math.Ln2*float64(dt)/float64(e.halfLife))
+		e.rate += alpha * (r - e.rate)
+	}
+	e.last, e.pending = t, 0
+}
+
+func (e *EWMA) Rate() Rate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return Rate(e.rate)
+}
+
+// Window is a moving average of a transfer rate over a fixed window,
+// safe for concurrent use. Samples closer than 1/64 of the window
+// are merged to bound the memory and the time of Rate.
+type Window struct {
+	size time.Duration
+
+	mu      sync.Mutex
+	samples []rateSample
+}
+
+type rateSample struct {
+	t time.Time
+	n uint64
+}
+
+// windowSamples bounds the number of samples in a window,
+// there are at most about two samples per 1/windowSamples of it.
+const windowSamples = 64
+
+func NewWindow(size time.Duration) *Window {
+	return &Window{size: size}
+}
+
+func (w *Window) Add(n uint64, t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if k := len(w.samples); k >= 2 && t.Sub(w.samples[k-2].t) < w.size/windowSamples {
+		w.samples[k-1].n += n
+		w.samples[k-1].t = t
+	} else {
+		w.samples = append(w.samples, rateSample{t: t, n: n})
+	}
+	i := 0
+	for i < len(w.samples)-1 && t.Sub(w.samples[i+1].t) >= w.size {
+		i++
+	}
+	w.samples = append(w.samples[:0], w.samples[i:]...)
+}
+
+func (w *Window) Rate() Rate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.samples) < 2 {
+		return 0
+	}
+	first, last := w.samples[0], w.samples[len(w.samples)-1]
+	dt := last.t.Sub(first.t)
+	if dt <= 0 {
+		return 0
+	}
+	var n uint64
+	for _, s := range w.samples[1:] {
+		n += s.n
+	}
+	return Rate(float64(n) / dt.Seconds())
+}
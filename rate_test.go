@@ -0,0 +1,194 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var rateFormatTests = []struct {
+	name   string
+	line   string
+	rate   bytefmt.Rate
+	format string
+	want   string
+}{
+	{
+		name:   "general format",
+		line:   testline(),
+		rate:   1536,
+		format: "%v",
+		want:   "1.5K/s",
+	}, {
+		name:   "precision",
+		line:   testline(),
+		rate:   1128,
+		format: "% .2f",
+		want:   "1.10 K/s",
+	}, {
+		name:   "zero",
+		line:   testline(),
+		rate:   0,
+		format: "%v",
+		want:   "0B/s",
+	}, {
+		name:   "negative",
+		line:   testline(),
+		rate:   -1,
+		format: "%v",
+		want:   "0B/s",
+	}, {
+		name:   "not a number",
+		line:   testline(),
+		rate:   bytefmt.Rate(math.NaN()),
+		format: "%v",
+		want:   "0B/s",
+	}, {
+		name:   "width",
+		line:   testline(),
+		rate:   1536,
+		format: "%10v|",
+		want:   "    1.5K/s|",
+	}, {
+		name:   "left-justified width",
+		line:   testline(),
+		rate:   1536,
+		format: "%-10v|",
+		want:   "1.5K/s    |",
+	}, {
+		name:   "width with a space",
+		line:   testline(),
+		rate:   1128,
+		format: "% 12.1f|",
+		want:   "     1.1 K/s|",
+	}, {
+		name:   "quoted",
+		line:   testline(),
+		rate:   1536,
+		format: "%q",
+		want:   `"1.5K/s"`,
+	}, {
+		name:   "quoted width",
+		line:   testline(),
+		rate:   1536,
+		format: "%10q|",
+		want:   `  "1.5K/s"|`,
+	},
+}
+
+func TestRateFormat(t *testing.T) {
+	for _, tt := range rateFormatTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			got := fmt.Sprintf(tt.format, tt.rate)
+			if got != tt.want {
+				t.Errorf("\nwant rate: %#v\n got rate: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestRateString(t *testing.T) {
+	got := bytefmt.Rate(12.3 * 1024 * 1024).String()
+	if got != "12.3M/s" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "12.3M/s", got)
+	}
+}
+
+type rateSample struct {
+	n  uint64
+	dt time.Duration
+}
+
+var estimatorTests = []struct {
+	name      string
+	line      string
+	estimator func() bytefmt.Estimator
+	samples   []rateSample
+	want      float64
+}{
+	{
+		name:      "ewma steady",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewEWMA(5 * time.Second) },
+		samples:   []rateSample{{0, 0}, {1000, time.Second}, {1000, time.Second}, {1000, time.Second}},
+		want:      1000,
+	}, {
+		name:      "ewma burst after one half-life",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewEWMA(time.Second) },
+		samples:   []rateSample{{0, 0}, {1000, time.Second}, {3000, time.Second}},
+		want:      2000,
+	}, {
+		name:      "ewma samples at the same time",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewEWMA(time.Second) },
+		samples:   []rateSample{{0, 0}, {500, 0}, {500, time.Second}},
+		want:      1000,
+	}, {
+		name:      "ewma stall",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewEWMA(time.Second) },
+		samples:   []rateSample{{0, 0}, {1000, time.Second}, {0, 2 * time.Second}},
+		want:      250,
+	}, {
+		name:      "ewma single sample",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewEWMA(time.Second) },
+		samples:   []rateSample{{1000, 0}},
+		want:      0,
+	}, {
+		name:      "window not full",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewWindow(10 * time.Second) },
+		samples:   []rateSample{{0, 0}, {1000, time.Second}, {3000, time.Second}},
+		want:      2000,
+	}, {
+		name:      "window drops old samples",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewWindow(2 * time.Second) },
+		samples:   []rateSample{{0, 0}, {9000, time.Second}, {1000, time.Second}, {1000, time.Second}},
+		want:      1000,
+	}, {
+		name:      "window merges close samples",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewWindow(time.Second) },
+		samples:   []rateSample{{0, 0}, {100, time.Millisecond}, {100, time.Millisecond}, {100, time.Millisecond}, {700, 997 * time.Millisecond}},
+		want:      1000,
+	}, {
+		name:      "window single sample",
+		line:      testline(),
+		estimator: func() bytefmt.Estimator { return bytefmt.NewWindow(time.Second) },
+		samples:   []rateSample{{1000, 0}},
+		want:      0,
+	},
+}
+
+func TestEstimator(t *testing.T) {
+	for _, tt := range estimatorTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := tt.estimator()
+			now := time.Unix(0, 0)
+			for _, s := range tt.samples {
+				now = now.Add(s.dt)
+				e.Add(s.n, now)
+			}
+			got := e.Rate()
+			if math.Abs(float64(got)-tt.want) > 1e-9 {
+				t.Errorf("\nwant rate: %v\n got rate: %v\ntest: %s", tt.want, float64(got), tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ratio is a used size out of a total, e.g. 1.5G / 4G (37.5%).
+type Ratio struct {
+	Used  Bytes
+	Total Bytes
+}
+
+// NewRatio returns the ratio of used to total.
+func NewRatio(used, total Bytes) Ratio {
+	return Ratio{Used: used, Total: total}
+}
+
+// Percent returns the used percentage of the total, 0 if the total is zero.
+func (r Ratio) Percent() float64 {
+	if r.Total.Value == 0 {
+		return 0
+	}
+	return float64(r.Used.Value) / float64(r.Total.Value) * 100
+}
+
+// String returns the ratio with at most one decimal, e.g. 1.5G / 4G (37.5%),
+// the percentage is a dash if the total is zero.
+func (r Ratio) String() string {
+	return r.Used.short() + " / " + r.Total.short() + " (" + r.percent(-1) + ")"
+}
+
+// percent returns the percentage with the precision,
+// at most one decimal if it is negative.
+func (r Ratio) percent(prec int) string {
+	if r.Total.Value == 0 {
+		return "-"
+	}
+	if prec < 0 {
+		return strings.TrimSuffix(strconv.FormatFloat(r.Percent(), 'f', 1, 64), ".0") + "%"
+	}
+	return strconv.FormatFloat(r.Percent(), 'f', prec, 64) + "%"
+}
+
+/*
+	The sizes are formatted by the verb, the flags + and ' ' (space)
+	and the precision as Bytes does, the precision also applies
+	to the percentage. Other flags:
+		#	percentage first (%#v): 37.5% of 4G;
+		-	pad with spaces on the right rather than the left;
+	the width applies to the whole ratio.
+*/
+
+func (r Ratio) Format(f fmt.State, c rune) {
+	prec, ok := f.Precision()
+	if !ok {
+		prec = -1
+	}
+	var used, total string
+	if (c == 'v' || c == 's') && !ok && !f.Flag('+') && !f.Flag(' ') {
+		used, total = r.Used.short(), r.Total.short()
+	} else {
+		vf := "%"
+		if f.Flag('+') {
+			vf += "+"
+		}
+		if f.Flag(' ') {
+			vf += " "
+		}
+		if ok {
+			vf += "." + strconv.Itoa(prec)
+		}
+		vf += string(c)
+		used, total = fmt.Sprintf(vf, r.Used), fmt.Sprintf(vf, r.Total)
+	}
+	var s string
+	if f.Flag('#') {
+		s = r.percent(prec) + " of " + total
+	} else {
+		s = used + " / " + total + " (" + r.percent(prec) + ")"
+	}
+	if w, ok := f.Width(); ok {
+		if f.Flag('-') {
+			s = padRight(s, w)
+		} else {
+			s = padLeft(s, w)
+		}
+	}
+	_, _ = f.Write([]byte(s))
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var ratioTests = []struct {
+	name   string
+	line   string
+	used   uint64
+	total  uint64
+	format string
+	want   string
+}{
+	{
+		name:   "string",
+		line:   testline(),
+		used:   1610612736,
+		total:  4 << 30,
+		format: "%v",
+		want:   "1.5G / 4G (37.5%)",
+	}, {
+		name:   "whole percent",
+		line:   testline(),
+		used:   512 << 20,
+		total:  1 << 30,
+		format: "%s",
+		want:   "512M / 1G (50%)",
+	}, {
+		name:   "precision",
+		line:   testline(),
+		used:   1 << 30,
+		total:  3 << 30,
+		format: "%.2f",
+		want:   "1.00G / 3.00G (33.33%)",
+	}, {
+		name:   "space",
+		line:   testline(),
+		used:   1610612736,
+		total:  4 << 30,
+		format: "% .1f",
+		want:   "1.5 G / 4.0 G (37.5%)",
+	}, {
+		name:   "percentage first",
+		line:   testline(),
+		used:   1610612736,
+		total:  4 << 30,
+		format: "%#v",
+		want:   "37.5% of 4G",
+	}, {
+		name:   "integers",
+		line:   testline(),
+		used:   1 << 30,
+		total:  3 << 30,
+		format: "%.0d",
+		want:   "1G / 3G (33%)",
+	}, {
+		name:   "width",
+		line:   testline(),
+		used:   1 << 20,
+		total:  2 << 20,
+		format: "%20v|",
+		want:   "       1M / 2M (50%)|",
+	}, {
+		name:   "left",
+		line:   testline(),
+		used:   1 << 20,
+		total:  2 << 20,
+		format: "%#-14v|",
+		want:   "50% of 2M     |",
+	}, {
+		name:   "zero total",
+		line:   testline(),
+		format: "%v",
+		want:   "0B / 0B (-)",
+	}, {
+		name:   "more than total",
+		line:   testline(),
+		used:   3 << 30,
+		total:  2 << 30,
+		format: "%v",
+		want:   "3G / 2G (150%)",
+	},
+}
+
+func TestRatio(t *testing.T) {
+	for _, tt := range ratioTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			r := bytefmt.NewRatio(bytefmt.New(tt.used), bytefmt.New(tt.total))
+			if got := fmt.Sprintf(tt.format, r); got != tt.want {
+				t.Errorf("\nwant format: %#v\n got format: %#v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestRatioString(t *testing.T) {
+	r := bytefmt.NewRatio(bytefmt.New(1610612736), bytefmt.New(4<<30))
+	if got := r.String(); got != "1.5G / 4G (37.5%)" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "1.5G / 4G (37.5%)", got)
+	}
+	if got := r.Percent(); got != 37.5 {
+		t.Errorf("\nwant percent: %v\n got percent: %v", 37.5, got)
+	}
+	if got := bytefmt.NewRatio(bytefmt.New(1), bytefmt.New(0)).Percent(); got != 0 {
+		t.Errorf("\nwant percent: %v\n got percent: %v", 0, got)
+	}
+}
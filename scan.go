@@ -0,0 +1,168 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Match is a size found in a text.
+type Match struct {
+	Start int // byte offset of the first byte of the size
+	End   int // byte offset after the last byte of the size
+	Value Bytes
+}
+
+// Scanner finds sizes such as "Xmx2g", "1,024 KB", "3.5GiB"
+// or "512m" in free-form text. The units of measure are those
+// accepted by Parse, the numbers may group thousands with commas.
+// Only units of more than one letter may be separated from the number
+// by a blank, so that "5 m" is not mistaken for a size.
+type Scanner struct {
+	// Bare also matches numbers without a unit of measure
+	// as numbers of bytes. Only numbers with a unit are matched
+	// by default as bare numbers are often something else,
+	// e.g. counts, ports or dates.
+	Bare bool
+}
+
+// Find returns the sizes in the text in order.
+func (s *Scanner) Find(text string) []Match {
+	var matches []Match
+	for i := 0; i < len(text); {
+		if !isDigit(text[i]) || i > 0 && (isDigit(text[i-1]) || text[i-1] == '.' || text[i-1] == ',') {
+			i++
+			continue
+		}
+		m, next, ok := s.match(text, i)
+		if ok {
+			matches = append(matches, m)
+		}
+		i = next
+	}
+	return matches
+}
+
+// match matches a size at the start of the number at i
+// and returns the offset to continue the search from.
+func (s *Scanner) match(text string, i int) (Match, int, bool) {
+	end := scanNumber(text, i)
+	num := strings.Replace(text[i:end], ",", "", -1)
+	if !sizeStart(text, i) {
+		return Match{}, end, false
+	}
+	j := end
+	if j < len(text) && isBlank(text[j]) {
+		j++
+	}
+	k := j
+	for k < len(text) && isLetter(text[k]) {
+		k++
+	}
+	// A single letter is a unit only right after the number.
+	if k > j && (j == end || k-j > 1) && sizeEnd(text, k) {
+		if _, ok := parseUnit(text[j:k]); ok {
+			v, err := parse(num + text[j:k])
+			if err != nil {
+				return Match{}, k, false
+			}
+			return Match{Start: i, End: k, Value: New(v)}, k, true
+		}
+	}
+	if !s.Bare || !sizeEnd(text, end) {
+		return Match{}, end, false
+	}
+	v, err := parse(num)
+	if err != nil {
+		return Match{}, end, false
+	}
+	return Match{Start: i, End: end, Value: New(v)}, end, true
+}
+
+// scanNumber returns the end of the number at i such as 1,024.5.
+func scanNumber(text string, i int) int {
+	j := i
+	for j < len(text) && isDigit(text[j]) {
+		j++
+	}
+	// Thousands are grouped after one to three digits.
+	if j-i <= 3 {
+		for j+4 <= len(text) && text[j] == ',' &&
+			isDigit(text[j+1]) && isDigit(text[j+2]) && isDigit(text[j+3]) &&
+			(j+4 == len(text) || !isDigit(text[j+4])) {
+			j += 4
+		}
+	}
+	if j+1 < len(text) && text[j] == '.' && isDigit(text[j+1]) {
+		j++
+		for j < len(text) && isDigit(text[j]) {
+			j++
+		}
+	}
+	return j
+}
+
+// sizePrefixes are the options of the JVM allowed right before a size,
+// e.g. -Xmx2g; -XX:MaxMetaspaceSize=256m needs none.
+var sizePrefixes = []string{"Xmx", "Xms", "Xss", "Xmn"}
+
+// sizeStart reports whether a size may start at i of the text,
+// that is it is not part of a word such as e2e or p2p,
+// unless the word is one of the sizePrefixes.
+func sizeStart(text string, i int) bool {
+	if i == 0 || !isWord(text[i-1]) {
+		return true
+	}
+	for _, p := range sizePrefixes {
+		if strings.HasSuffix(text[:i], p) && (i == len(p) || !isWord(text[i-len(p)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeEnd reports whether a size may end at i of the text,
+// that is it is not followed by a letter, digit or another number.
+func sizeEnd(text string, i int) bool {
+	if i == len(text) {
+		return true
+	}
+	c := text[i]
+	if isWord(c) {
+		return false
+	}
+	return !((c == '.' || c == ',') && i+1 < len(text) && isDigit(text[i+1]))
+}
+
+// Replace returns a copy of the text with the sizes replaced
+// by the results of the function.
+func (s *Scanner) Replace(text string, f func(Match) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range s.Find(text) {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(f(m))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Normalize returns a copy of the text with the sizes formatted
+// by the format, e.g. "% .1f", see Bytes.Format; as a number
+// with at most one decimal such as 1.5G if the format is empty.
+func (s *Scanner) Normalize(text, format string) string {
+	return s.Replace(text, func(m Match) string {
+		if format == "" {
+			return m.Value.short()
+		}
+		return fmt.Sprintf(format, m.Value)
+	})
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return c|0x20 >= 'a' && c|0x20 <= 'z' }
+func isWord(c byte) bool   { return isLetter(c) || isDigit(c) || c == '_' }
@@ -0,0 +1,144 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var scannerTests = []struct {
+	name string
+	line string
+	text string
+	bare bool
+	want string // matches as text=value
+	norm string // normalized with "% .1f"
+}{
+	{
+		name: "jvm",
+		line: testline(),
+		text: "java -Xms512m -Xmx2g -jar app.jar",
+		want: "512m=536870912 2g=2147483648",
+		norm: "java -Xms512.0 M -Xmx2.0 G -jar app.jar",
+	}, {
+		name: "thousands",
+		line: testline(),
+		text: "read 1,024 KB in 3 ms",
+		want: "1,024 KB=1024000",
+		norm: "read 1000.0 K in 3 ms",
+	}, {
+		name: "iec",
+		line: testline(),
+		text: "heap: 3.5GiB/8GiB (43%)",
+		want: "3.5GiB=3758096384 8GiB=8589934592",
+		norm: "heap: 3.5 G/8.0 G (43%)",
+	}, {
+		name: "nginx",
+		line: testline(),
+		text: "client intended to send too large body: 10485761 bytes",
+		want: "10485761 bytes=10485761",
+		norm: "client intended to send too large body: 10.0 M",
+	}, {
+		name: "bits",
+		line: testline(),
+		text: "link 100Mbit, 1Gbits.",
+		want: "100Mbit=12500000 1Gbits=125000000",
+		norm: "link 11.9 M, 119.2 M.",
+	}, {
+		name: "bare numbers ignored",
+		line: testline(),
+		text: "pid 4242 wrote 4096 to port 8080",
+		norm: "pid 4242 wrote 4096 to port 8080",
+	}, {
+		name: "bare numbers",
+		line: testline(),
+		text: "pid 4242 wrote 4096, 1,536 and 2K",
+		bare: true,
+		want: "4242=4242 4096=4096 1,536=1536 2K=2048",
+		norm: "pid 4.1 K wrote 4.0 K, 1.5 K and 2.0 K",
+	}, {
+		name: "bare numbers in words",
+		line: testline(),
+		text: "x86_64 sha256 2go v1.2.3 10.0.0.1 2022-01-02",
+		bare: true,
+		want: "2022=2022 01=1 02=2",
+		norm: "x86_64 sha256 2go v1.2.3 10.0.0.1 2.0 K-1.0 B-2.0 B",
+	}, {
+		name: "sizes in words",
+		line: testline(),
+		text: "run e2e tests over p2p and b2b links, see RFC4k_2m",
+		norm: "run e2e tests over p2p and b2b links, see RFC4k_2m",
+	}, {
+		name: "jvm prefixes",
+		line: testline(),
+		text: "Xss1m -Xmn256m -XX:MaxMetaspaceSize=128m fooXmx2g",
+		want: "1m=1048576 256m=268435456 128m=134217728",
+		norm: "Xss1.0 M -Xmn256.0 M -XX:MaxMetaspaceSize=128.0 M fooXmx2g",
+	}, {
+		name: "not units",
+		line: testline(),
+		text: "retry in 10s, 5min or 2 hours; 3 GB",
+		want: "3 GB=3000000000",
+		norm: "retry in 10s, 5min or 2 hours; 2.8 G",
+	}, {
+		name: "single letters after a blank",
+		line: testline(),
+		text: "timeout=5m, after 5 m or 2 k; 3 KB",
+		want: "5m=5242880 3 KB=3000",
+		norm: "timeout=5.0 M, after 5 m or 2 k; 2.9 K",
+	}, {
+		name: "out of range",
+		line: testline(),
+		text: "100EiB 1K",
+		want: "1K=1024",
+		norm: "100EiB 1.0 K",
+	}, {
+		name: "thousands group too long",
+		line: testline(),
+		text: "1,0240K",
+		norm: "1,0240K",
+	},
+}
+
+func TestScanner(t *testing.T) {
+	for _, tt := range scannerTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := bytefmt.Scanner{Bare: tt.bare}
+			var got []string
+			for _, m := range s.Find(tt.text) {
+				got = append(got, fmt.Sprintf("%s=%d", tt.text[m.Start:m.End], m.Value.Value))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("\nwant matches: %#v\n got matches: %#v\ntest: %s", tt.want, strings.Join(got, " "), tt.line)
+			}
+			if norm := s.Normalize(tt.text, "% .1f"); norm != tt.norm {
+				t.Errorf("\nwant normalized: %#v\n got normalized: %#v\ntest: %s", tt.norm, norm, tt.line)
+			}
+		})
+	}
+}
+
+func TestScannerNormalizeShort(t *testing.T) {
+	var s bytefmt.Scanner
+	got := s.Normalize("-Xmx2048m, 1,536 KiB", "")
+	if want := "-Xmx2G, 1.5M"; got != want {
+		t.Errorf("\nwant normalized: %#v\n got normalized: %#v", want, got)
+	}
+}
+
+func TestScannerNormalizeDurations(t *testing.T) {
+	var s bytefmt.Scanner
+	got := s.Normalize("timeout=5m, after 5 m", "")
+	if want := "timeout=5M, after 5 m"; got != want {
+		t.Errorf("\nwant normalized: %#v\n got normalized: %#v", want, got)
+	}
+}
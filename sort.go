@@ -0,0 +1,95 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Compare compares the sizes at the start of a and b, such as "900K",
+// "1.2M", "15GB" or "4.0K\tdir", by value, returning -1, 0 or +1.
+// The units of measure are those accepted by Parse.
+// Strings without a size come first, then negative sizes;
+// strings with sizes of equal value are compared lexically.
+func Compare(a, b string) int {
+	if c := humanKey(a).cmp(humanKey(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// HumanSlice attaches the methods of sort.Interface to []string,
+// sorting by Compare in increasing order.
+type HumanSlice []string
+
+func (x HumanSlice) Len() int           { return len(x) }
+func (x HumanSlice) Less(i, j int) bool { return Compare(x[i], x[j]) < 0 }
+func (x HumanSlice) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
+
+// SortHuman sorts a slice of strings by the sizes at their start
+// in increasing order, as sort -h does.
+func SortHuman(x []string) { sort.Sort(HumanSlice(x)) }
+
+// humanSize is the size at the start of a string.
+type humanSize struct {
+	ok  bool
+	neg bool
+	v   uint64 // saturated at math.MaxUint64
+}
+
+func humanKey(s string) humanSize {
+	s = strings.TrimLeft(s, " \t")
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	if i == 0 {
+		return humanSize{}
+	}
+	// As in sort -h, the unit follows the number without blanks.
+	k := i
+	for k < len(s) && isLetter(s[k]) {
+		k++
+	}
+	v, err := parse(s[:k])
+	if err != nil && !errors.Is(err, ErrRange) {
+		// Not a unit, e.g. "10files".
+		v, err = parse(s[:i])
+	}
+	if errors.Is(err, ErrRange) {
+		v, err = math.MaxUint64, nil
+	}
+	if err != nil {
+		return humanSize{}
+	}
+	return humanSize{ok: true, neg: neg && v != 0, v: v}
+}
+
+func (x humanSize) cmp(y humanSize) int {
+	switch {
+	case x.ok != y.ok:
+		if x.ok {
+			return 1
+		}
+		return -1
+	case x.neg != y.neg:
+		if x.neg {
+			return -1
+		}
+		return 1
+	case x.v == y.v:
+		return 0
+	case x.v < y.v != x.neg:
+		return -1
+	}
+	return 1
+}
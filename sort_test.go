@@ -0,0 +1,131 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var compareTests = []struct {
+	name string
+	line string
+	a    string
+	b    string
+	want int
+}{
+	{
+		name: "units",
+		line: testline(),
+		a:    "900K",
+		b:    "1.2M",
+		want: -1,
+	}, {
+		name: "value rather than unit",
+		line: testline(),
+		a:    "2000K",
+		b:    "1M",
+		want: 1,
+	}, {
+		name: "si and iec",
+		line: testline(),
+		a:    "1GB",
+		b:    "1GiB",
+		want: -1,
+	}, {
+		name: "equal values",
+		line: testline(),
+		a:    "1024",
+		b:    "1K",
+		want: -1,
+	}, {
+		name: "equal strings",
+		line: testline(),
+		a:    "1.5G",
+		b:    "1.5G",
+		want: 0,
+	}, {
+		name: "du output",
+		line: testline(),
+		a:    "12K\t./b",
+		b:    "4.0K\t./a",
+		want: 1,
+	}, {
+		name: "word after a blank",
+		line: testline(),
+		a:    "4096\tm",
+		b:    "8192\tdocs",
+		want: -1,
+	}, {
+		name: "unit-like word after a blank",
+		line: testline(),
+		a:    "100\tmb-data",
+		b:    "101\ta",
+		want: -1,
+	}, {
+		name: "letter after a blank",
+		line: testline(),
+		a:    "9 e",
+		b:    "10 kittens",
+		want: -1,
+	}, {
+		name: "not a unit",
+		line: testline(),
+		a:    "10 files",
+		b:    "9K",
+		want: -1,
+	}, {
+		name: "no size",
+		line: testline(),
+		a:    "total",
+		b:    "-1E",
+		want: -1,
+	}, {
+		name: "negative",
+		line: testline(),
+		a:    "-2K",
+		b:    "-1K",
+		want: -1,
+	}, {
+		name: "out of range",
+		line: testline(),
+		a:    "100E",
+		b:    "15E",
+		want: 1,
+	},
+}
+
+func TestCompare(t *testing.T) {
+	for _, tt := range compareTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := bytefmt.Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("\nwant compare: %d\n got compare: %d\ntest: %s", tt.want, got, tt.line)
+			}
+			if got := bytefmt.Compare(tt.b, tt.a); got != -tt.want {
+				t.Errorf("\nwant reverse compare: %d\n got reverse compare: %d\ntest: %s", -tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func TestSortHuman(t *testing.T) {
+	x := []string{"15G", "1.2M", "900K", "", "1.5GiB", "0", "512", "-4K", "2kB"}
+	bytefmt.SortHuman(x)
+	want := ",-4K,0,512,2kB,900K,1.2M,1.5GiB,15G"
+	if got := strings.Join(x, ","); got != want {
+		t.Errorf("\nwant sorted: %#v\n got sorted: %#v", want, got)
+	}
+	sort.Sort(sort.Reverse(bytefmt.HumanSlice(x)))
+	want = "15G,1.5GiB,1.2M,900K,2kB,512,0,-4K,"
+	if got := strings.Join(x, ","); got != want {
+		t.Errorf("\nwant reversed: %#v\n got reversed: %#v", want, got)
+	}
+}
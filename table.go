@@ -0,0 +1,224 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table collects rows and writes them in aligned columns as
+// text/tabwriter does. The sizes are aligned on the decimal point
+// with their units of measure in a column of their own, e.g.
+//
+//	name      size    used
+//	a      512   B   1.5 G
+//	b        1.5 G  12   K
+//	total    1.5 G   1.5 G
+type Table struct {
+	w      io.Writer
+	header []string
+	rows   [][]tableCell
+	from   int // first row of the current total
+
+	// Format formats the sizes, e.g. "%.2f", see Bytes.Format;
+	// a number with at most one decimal such as 1.5G if empty.
+	// The format should not have a width.
+	Format string
+	// Padding is the number of blanks between columns, 2 by default;
+	// negative values are treated as zero.
+	Padding int
+}
+
+type tableCell struct {
+	text string
+	size bool
+	b    Bytes
+}
+
+// NewTable returns a table writing to w on Flush.
+func NewTable(w io.Writer) *Table {
+	return &Table{w: w, Padding: 2}
+}
+
+// Header sets the header of the columns,
+// the headers of columns of sizes are aligned to the right.
+func (t *Table) Header(cols ...string) {
+	t.header = cols
+}
+
+// Row adds a row of cells, cells of type Bytes are sizes
+// and others are text formatted as by fmt.Sprint.
+func (t *Table) Row(cells ...interface{}) {
+	row := make([]tableCell, len(cells))
+	for i, c := range cells {
+		if b, ok := c.(Bytes); ok {
+			row[i] = tableCell{size: true, b: b}
+		} else {
+			row[i] = tableCell{text: fmt.Sprint(c)}
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Total adds a row of the sums of the sizes in each column
+// of the rows added since the previous total, with the label
+// in the first column without sizes. The sums saturate at the largest size.
+func (t *Table) Total(label string) {
+	var sums []Bytes
+	var size []bool
+	for _, row := range t.rows[t.from:] {
+		for i, c := range row {
+			for len(sums) <= i {
+				sums = append(sums, Bytes{})
+				size = append(size, false)
+			}
+			if c.size {
+				if size[i] {
+					sums[i] = SumSat(sums[i], c.b)
+				} else {
+					sums[i], size[i] = c.b, true
+				}
+			}
+		}
+	}
+	row := make([]tableCell, len(sums))
+	for i := range row {
+		if size[i] {
+			row[i] = tableCell{size: true, b: sums[i]}
+		} else if label != "" {
+			row[i] = tableCell{text: label}
+			label = ""
+		}
+	}
+	t.rows = append(t.rows, row)
+	t.from = len(t.rows)
+}
+
+// Flush writes the table and clears its rows.
+func (t *Table) Flush() error {
+	type column struct {
+		size  bool
+		width int // of text
+		whole int // width of the integer parts
+		frac  int // width of the fractional parts with the point
+		unit  int // width of the units of measure
+	}
+	var cols []column
+	col := func(i int) *column {
+		for len(cols) <= i {
+			cols = append(cols, column{})
+		}
+		return &cols[i]
+	}
+	type sizeText struct{ whole, frac, unit string }
+	texts := make([][]sizeText, len(t.rows))
+	for r, row := range t.rows {
+		texts[r] = make([]sizeText, len(row))
+		for i, c := range row {
+			cl := col(i)
+			if !c.size {
+				cl.width = max(cl.width, width(c.text))
+				continue
+			}
+			var s string
+			if t.Format == "" {
+				s = c.b.short()
+			} else {
+				s = fmt.Sprintf(t.Format, c.b)
+			}
+			n := strings.IndexFunc(s, func(r rune) bool {
+				return !(r >= '0' && r <= '9' || r == '.' || r == '+' || r == '-')
+			})
+			if n == -1 {
+				n = len(s)
+			}
+			st := sizeText{whole: s[:n], unit: strings.TrimLeft(s[n:], " ")}
+			if p := strings.IndexByte(st.whole, '.'); p != -1 {
+				st.whole, st.frac = st.whole[:p], st.whole[p:]
+			}
+			texts[r][i] = st
+			cl.size = true
+			cl.whole = max(cl.whole, len(st.whole))
+			cl.frac = max(cl.frac, len(st.frac))
+			cl.unit = max(cl.unit, width(st.unit))
+		}
+	}
+	for i, h := range t.header {
+		col(i).width = max(col(i).width, width(h))
+	}
+	for i := range cols {
+		c := &cols[i]
+		if c.size {
+			w := c.whole + c.frac
+			if c.unit > 0 {
+				w += 1 + c.unit
+			}
+			c.width = max(c.width, w)
+		}
+	}
+
+	var b strings.Builder
+	pad := strings.Repeat(" ", max(t.Padding, 0))
+	line := func(cells []string) {
+		s := strings.Join(cells, pad)
+		b.WriteString(strings.TrimRight(s, " ") + "\n")
+	}
+	if t.header != nil {
+		cells := make([]string, len(t.header))
+		for i, h := range t.header {
+			if cols[i].size {
+				cells[i] = padLeft(h, cols[i].width)
+			} else {
+				cells[i] = padRight(h, cols[i].width)
+			}
+		}
+		line(cells)
+	}
+	for r, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cl := cols[i]
+			if !c.size {
+				cells[i] = padRight(c.text, cl.width)
+				continue
+			}
+			st := texts[r][i]
+			s := padLeft(st.whole, cl.whole) + padRight(st.frac, cl.frac)
+			if cl.unit > 0 {
+				s += " " + padRight(st.unit, cl.unit)
+			}
+			cells[i] = padLeft(s, cl.width)
+		}
+		line(cells)
+	}
+	t.rows, t.from = nil, 0
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func width(s string) int { return len([]rune(s)) }
+
+func padLeft(s string, w int) string {
+	if n := w - width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+func padRight(s string, w int) string {
+	if n := w - width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
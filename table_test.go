@@ -0,0 +1,115 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var tableTests = []struct {
+	name  string
+	line  string
+	build func(t *bytefmt.Table)
+	want  string
+}{
+	{
+		name: "decimal points and units",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Header("name", "size", "used")
+			t.Row("a", bytefmt.New(512), bytefmt.New(1610612736))
+			t.Row("b", bytefmt.New(1610612736), bytefmt.New(12288))
+			t.Total("total")
+		},
+		want: "name      size    used\n" +
+			"a      512   B   1.5 G\n" +
+			"b        1.5 G  12   K\n" +
+			"total    1.5 G   1.5 G\n",
+	}, {
+		name: "format",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Format = "%.2f"
+			t.Padding = 1
+			t.Row(bytefmt.New(1<<20, "B", "KiB", "MiB"), "x")
+			t.Row(bytefmt.New(1000), "long text")
+			t.Row(bytefmt.New(123456789, "B", "KiB", "MiB"), "")
+		},
+		want: "   1.00 MiB x\n" +
+			"1000.00 B   long text\n" +
+			" 117.74 MiB\n",
+	}, {
+		name: "subtotals",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Header("dir", "", "apparent")
+			t.Row("a", 1, bytefmt.New(1024))
+			t.Row("b", 2, bytefmt.New(2048))
+			t.Total("sub")
+			t.Row("c", 3, bytefmt.New(1<<30))
+			t.Total("sub")
+		},
+		want: "dir     apparent\n" +
+			"a    1       1 K\n" +
+			"b    2       2 K\n" +
+			"sub          3 K\n" +
+			"c    3       1 G\n" +
+			"sub          1 G\n",
+	}, {
+		name: "header wider than sizes",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Header("allocated")
+			t.Row(bytefmt.New(1536))
+			t.Row(bytefmt.New(10 << 20))
+		},
+		want: "allocated\n" +
+			"    1.5 K\n" +
+			"   10   M\n",
+	}, {
+		name: "negative padding",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Padding = -1
+			t.Row("a", bytefmt.New(1536))
+			t.Row("bc", bytefmt.New(10<<20))
+		},
+		want: "a  1.5 K\n" +
+			"bc10   M\n",
+	}, {
+		name: "saturated total",
+		line: testline(),
+		build: func(t *bytefmt.Table) {
+			t.Row(bytefmt.New(1<<63), "x")
+			t.Row(bytefmt.New(1<<63), "y")
+			t.Total("")
+		},
+		want: " 8 E  x\n" +
+			" 8 E  y\n" +
+			"16 E\n",
+	},
+}
+
+func TestTable(t *testing.T) {
+	for _, tt := range tableTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			tb := bytefmt.NewTable(&b)
+			tt.build(tb)
+			if err := tb.Flush(); err != nil {
+				t.Fatalf("\nunexpected error: %s\ntest: %s", err, tt.line)
+			}
+			if b.String() != tt.want {
+				t.Errorf("\nwant table: %#v\n got table: %#v\ntest: %s", tt.want, b.String(), tt.line)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Unit is a unit of measure, its value is the size of the unit in bits.
+// Units may be multiplied, e.g. 4 * KiB is the size of a memory page.
+type Unit uint64
+
+const (
+	Bit Unit = 1
+	B   Unit = 8
+)
+
+// SI (decimal) multiples of a byte.
+const (
+	KB Unit = 1000 * B
+	MB Unit = 1000 * KB
+	GB Unit = 1000 * MB
+	TB Unit = 1000 * GB
+	PB Unit = 1000 * TB
+	EB Unit = 1000 * PB
+)
+
+// IEC (binary) multiples of a byte.
+const (
+	KiB Unit = 1024 * B
+	MiB Unit = 1024 * KiB
+	GiB Unit = 1024 * MiB
+	TiB Unit = 1024 * GiB
+	PiB Unit = 1024 * TiB
+	EiB Unit = 1024 * PiB
+)
+
+// SI (decimal) multiples of a bit.
+const (
+	Kbit Unit = 1000 * Bit
+	Mbit Unit = 1000 * Kbit
+	Gbit Unit = 1000 * Mbit
+	Tbit Unit = 1000 * Gbit
+	Pbit Unit = 1000 * Tbit
+	Ebit Unit = 1000 * Pbit
+)
+
+// IEC (binary) multiples of a bit.
+const (
+	Kibit Unit = 1024 * Bit
+	Mibit Unit = 1024 * Kibit
+	Gibit Unit = 1024 * Mibit
+	Tibit Unit = 1024 * Gibit
+	Pibit Unit = 1024 * Tibit
+	Eibit Unit = 1024 * Pibit
+)
+
+var unitNames = map[Unit]string{
+	Bit: "bit", B: "B",
+	KB: "kB", MB: "MB", GB: "GB", TB: "TB", PB: "PB", EB: "EB",
+	KiB: "KiB", MiB: "MiB", GiB: "GiB", TiB: "TiB", PiB: "PiB", EiB: "EiB",
+	Kbit: "kbit", Mbit: "Mbit", Gbit: "Gbit", Tbit: "Tbit", Pbit: "Pbit", Ebit: "Ebit",
+	Kibit: "Kibit", Mibit: "Mibit", Gibit: "Gibit", Tibit: "Tibit", Pibit: "Pibit", Eibit: "Eibit",
+}
+
+// String returns the symbol of the unit, e.g. "MiB",
+// or its size for units without a symbol, e.g. "4096B".
+func (u Unit) String() string {
+	if n, ok := unitNames[u]; ok {
+		return n
+	}
+	if u%B == 0 {
+		return strconv.FormatUint(uint64(u/B), 10) + "B"
+	}
+	return strconv.FormatUint(uint64(u), 10) + "bit"
+}
+
+/*
+	Units of measure accepted by ParseUnit (case-insensitive):
+		B, byte, bytes	a byte;
+		K, M, G, T, P, E	binary multiples of a byte as printed by Bytes;
+		KiB, MiB, GiB, TiB, PiB, EiB	binary multiples of a byte;
+		KB, MB, GB, TB, PB, EB	decimal multiples of a byte;
+		bit, bits	a bit;
+		Kibit, Mibit, Gibit, Tibit, Pibit, Eibit	binary multiples of a bit;
+		Kbit, Mbit, Gbit, Tbit, Pbit, Ebit	decimal multiples of a bit.
+*/
+
+// ParseUnit parses a unit symbol such as "MiB" or "Gbit".
+func ParseUnit(s string) (Unit, error) {
+	u, ok := parseUnit(s)
+	if !ok {
+		return 0, &ParseError{Input: s, Err: ErrSyntax}
+	}
+	return u, nil
+}
+
+func parseUnit(s string) (Unit, bool) {
+	s = strings.ToLower(s)
+	switch s {
+	case "", "b", "byte", "bytes":
+		return B, true
+	case "bit", "bits":
+		return Bit, true
+	}
+	p := strings.IndexByte("kmgtpe", s[0])
+	if p == -1 {
+		return 0, false
+	}
+	bin, dec := Unit(1)<<(10*uint(p+1)), Unit(pow10(3*(p+1)))
+	switch s[1:] {
+	case "", "ib":
+		return bin * B, true
+	case "b":
+		return dec * B, true
+	case "ibit", "ibits":
+		return bin * Bit, true
+	case "bit", "bits":
+		return dec * Bit, true
+	}
+	return 0, false
+}
+
+// In returns b in units of u.
+func (b Bytes) In(u Unit) float64 {
+	return float64(b.Value) * float64(B) / float64(u)
+}
+
+// DivMod returns the number of whole units of u in b
+// and the remainder, rounded down to whole bytes.
+// The number of units saturates at math.MaxUint64,
+// which is only possible for units smaller than a byte.
+// DivMod panics if u is zero.
+func (b Bytes) DivMod(u Unit) (uint64, Bytes) {
+	if u == 0 {
+		panic("bytefmt: division by zero unit")
+	}
+	hi, lo := bits.Mul64(b.Value, uint64(B))
+	if hi >= uint64(u) {
+		return math.MaxUint64, b.with(0)
+	}
+	q, r := bits.Div64(hi, lo, uint64(u))
+	return q, b.with(r / uint64(B))
+}
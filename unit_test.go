@@ -0,0 +1,288 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytefmt_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/pfmt/bytefmt"
+)
+
+var bytesInTests = []struct {
+	name  string
+	line  string
+	bytes uint64
+	unit  bytefmt.Unit
+	want  float64
+	bench bool
+}{
+	{
+		name:  "bytes",
+		line:  testline(),
+		bytes: 1500,
+		unit:  bytefmt.B,
+		want:  1500,
+	}, {
+		name:  "decimal kilobytes",
+		line:  testline(),
+		bytes: 1500,
+		unit:  bytefmt.KB,
+		want:  1.5,
+		bench: true,
+	}, {
+		name:  "binary kilobytes",
+		line:  testline(),
+		bytes: 1536,
+		unit:  bytefmt.KiB,
+		want:  1.5,
+	}, {
+		name:  "binary exabytes",
+		line:  testline(),
+		bytes: 3 << 59,
+		unit:  bytefmt.EiB,
+		want:  1.5,
+	}, {
+		name:  "bits",
+		line:  testline(),
+		bytes: 3,
+		unit:  bytefmt.Bit,
+		want:  24,
+	}, {
+		name:  "decimal megabits",
+		line:  testline(),
+		bytes: 12500000,
+		unit:  bytefmt.Mbit,
+		want:  100,
+	}, {
+		name:  "binary kilobits",
+		line:  testline(),
+		bytes: 64,
+		unit:  bytefmt.Kibit,
+		want:  0.5,
+	}, {
+		name:  "pages",
+		line:  testline(),
+		bytes: 6 << 10,
+		unit:  4 * bytefmt.KiB,
+		want:  1.5,
+	},
+}
+
+func TestBytesIn(t *testing.T) {
+	for _, tt := range bytesInTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.unit.String()+" "+strconv.FormatUint(tt.bytes, 10), func(t *testing.T) {
+			t.Parallel()
+
+			got := bytefmt.New(tt.bytes).In(tt.unit)
+			if got != tt.want {
+				t.Errorf("\nwant in: %v\n got in: %v\ntest: %s", tt.want, got, tt.line)
+			}
+		})
+	}
+}
+
+func BenchmarkBytesIn(b *testing.B) {
+	b.ReportAllocs()
+
+	for _, tt := range bytesInTests {
+		if !tt.bench {
+			continue
+		}
+
+		b.Run(tt.line+"/"+tt.name+" "+tt.unit.String()+" "+strconv.FormatUint(tt.bytes, 10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = bytefmt.New(tt.bytes).In(tt.unit)
+			}
+		})
+	}
+}
+
+var bytesDivModTests = []struct {
+	name  string
+	line  string
+	bytes uint64
+	unit  bytefmt.Unit
+	quo   uint64
+	rem   uint64
+}{
+	{
+		name:  "pages",
+		line:  testline(),
+		bytes: 10000,
+		unit:  4 * bytefmt.KiB,
+		quo:   2,
+		rem:   1808,
+	}, {
+		name:  "decimal megabytes",
+		line:  testline(),
+		bytes: 2500000,
+		unit:  bytefmt.MB,
+		quo:   2,
+		rem:   500000,
+	}, {
+		name:  "less than a unit",
+		line:  testline(),
+		bytes: 1023,
+		unit:  bytefmt.KiB,
+		quo:   0,
+		rem:   1023,
+	}, {
+		name:  "decimal kilobits",
+		line:  testline(),
+		bytes: 300,
+		unit:  bytefmt.Kbit,
+		quo:   2,
+		rem:   50,
+	}, {
+		name:  "bits",
+		line:  testline(),
+		bytes: 1 << 60,
+		unit:  bytefmt.Bit,
+		quo:   1 << 63,
+		rem:   0,
+	}, {
+		name:  "too many bits",
+		line:  testline(),
+		bytes: math.MaxUint64,
+		unit:  bytefmt.Bit,
+		quo:   math.MaxUint64,
+		rem:   0,
+	}, {
+		name:  "largest",
+		line:  testline(),
+		bytes: math.MaxUint64,
+		unit:  bytefmt.EiB,
+		quo:   15,
+		rem:   1<<60 - 1,
+	},
+}
+
+func TestBytesDivMod(t *testing.T) {
+	for _, tt := range bytesDivModTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.unit.String()+" "+strconv.FormatUint(tt.bytes, 10), func(t *testing.T) {
+			t.Parallel()
+
+			quo, rem := bytefmt.New(tt.bytes).DivMod(tt.unit)
+			if quo != tt.quo || rem.Value != tt.rem {
+				t.Errorf("\nwant divmod: %d %d\n got divmod: %d %d\ntest: %s", tt.quo, tt.rem, quo, rem.Value, tt.line)
+			}
+		})
+	}
+}
+
+var unitTests = []struct {
+	name  string
+	line  string
+	input string
+	want  bytefmt.Unit
+	str   string
+	err   error
+}{
+	{
+		name:  "byte",
+		line:  testline(),
+		input: "B",
+		want:  bytefmt.B,
+		str:   "B",
+	}, {
+		name:  "bytes word",
+		line:  testline(),
+		input: "bytes",
+		want:  bytefmt.B,
+		str:   "B",
+	}, {
+		name:  "legacy name",
+		line:  testline(),
+		input: "M",
+		want:  bytefmt.MiB,
+		str:   "MiB",
+	}, {
+		name:  "binary",
+		line:  testline(),
+		input: "GiB",
+		want:  bytefmt.GiB,
+		str:   "GiB",
+	}, {
+		name:  "decimal",
+		line:  testline(),
+		input: "kB",
+		want:  bytefmt.KB,
+		str:   "kB",
+	}, {
+		name:  "decimal upper case",
+		line:  testline(),
+		input: "TB",
+		want:  bytefmt.TB,
+		str:   "TB",
+	}, {
+		name:  "bit",
+		line:  testline(),
+		input: "bit",
+		want:  bytefmt.Bit,
+		str:   "bit",
+	}, {
+		name:  "decimal bits",
+		line:  testline(),
+		input: "Gbit",
+		want:  bytefmt.Gbit,
+		str:   "Gbit",
+	}, {
+		name:  "binary bits",
+		line:  testline(),
+		input: "Pibit",
+		want:  bytefmt.Pibit,
+		str:   "Pibit",
+	}, {
+		name:  "exabits",
+		line:  testline(),
+		input: "Ebits",
+		want:  bytefmt.Ebit,
+		str:   "Ebit",
+	}, {
+		name:  "unknown",
+		line:  testline(),
+		input: "Zb",
+		err:   bytefmt.ErrSyntax,
+	}, {
+		name:  "too long",
+		line:  testline(),
+		input: "KiBB",
+		err:   bytefmt.ErrSyntax,
+	},
+}
+
+func TestParseUnit(t *testing.T) {
+	for _, tt := range unitTests {
+		tt := tt
+		t.Run(tt.line+"/"+tt.name+" "+tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := bytefmt.ParseUnit(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("\nwant error: %v\n got error: %v\ntest: %s", tt.err, err, tt.line)
+			}
+			if got != tt.want {
+				t.Errorf("\nwant unit: %d\n got unit: %d\ntest: %s", tt.want, got, tt.line)
+			}
+			if tt.err == nil && got.String() != tt.str {
+				t.Errorf("\nwant string: %#v\n got string: %#v\ntest: %s", tt.str, got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestUnitString(t *testing.T) {
+	if got := (4 * bytefmt.KiB).String(); got != "4096B" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "4096B", got)
+	}
+	if got := bytefmt.Unit(3).String(); got != "3bit" {
+		t.Errorf("\nwant string: %#v\n got string: %#v", "3bit", got)
+	}
+}